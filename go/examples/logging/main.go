@@ -66,7 +66,7 @@ func main() {
 
 	// Configure pulse with OTLP
 	pulseOpts := options.PulseOptions{
-		Telemetry: options.DefaultTelemetry(),
+		Telemetry: options.DefaultTelemetryForEnvironment(serviceOpts.Environment),
 	}
 	pulseOpts.Telemetry.OTLP.Enabled = true
 	pulseOpts.Telemetry.OTLP.Host = "localhost"
@@ -35,7 +35,7 @@ func main() {
 
 	// Configure Pulse with OTLP and MCAP
 	pulseOpts := options.PulseOptions{
-		Telemetry: options.DefaultTelemetry(),
+		Telemetry: options.DefaultTelemetryForEnvironment(serviceOpts.Environment),
 	}
 	pulseOpts.Telemetry.OTLP.Enabled = true
 	pulseOpts.Telemetry.OTLP.Host = "localhost"
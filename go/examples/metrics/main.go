@@ -37,7 +37,7 @@ func main() {
 
 	// Configure Pulse with OTLP (no MCAP for logs, only for metrics)
 	pulseOpts := options.PulseOptions{
-		Telemetry: options.DefaultTelemetry(),
+		Telemetry: options.DefaultTelemetryForEnvironment(serviceOpts.Environment),
 	}
 	pulseOpts.Telemetry.OTLP.Enabled = true
 	pulseOpts.Telemetry.OTLP.Host = "localhost"
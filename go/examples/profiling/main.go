@@ -43,7 +43,7 @@ func main() {
 	}
 
 	// Configure Pulse with comprehensive profiling
-	pulseOpts := options.Default()
+	pulseOpts := options.DefaultForEnvironment(serviceOpts.Environment)
 	pulseOpts.Profiling.Enabled = true
 	pulseOpts.Profiling.ServerAddress = "http://localhost:4040"
 
@@ -245,7 +245,8 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		span.SetAttribute("assistant.name", "Malenia")
 		span.SetAttribute("assistant.version", "1.0.0")
 
-		var totalTime float64
+		// Accumulated work is set on the span and recorded as counters when it ends
+		work := span.Metrics()
 
 		// Component 1: Input Processing
 		span.AddEvent("component_1_input_processing")
@@ -260,7 +261,7 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		}
 		span.SetAttribute("input.valid", inputResp.IsValid)
 		span.SetAttribute("input.tokens", inputResp.TokenCount)
-		totalTime += inputResp.ProcessingTimeMs
+		work.Add("pipeline.total_time_ms", inputResp.ProcessingTimeMs)
 
 		// Component 2: Context Retrieval
 		span.AddEvent("component_2_context_retrieval")
@@ -275,7 +276,7 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		}
 		span.SetAttribute("context.history_turns", contextResp.HistoryTurns)
 		span.SetAttribute("context.cache_hit", contextResp.CacheHit)
-		totalTime += contextResp.ProcessingTimeMs
+		work.Add("pipeline.total_time_ms", contextResp.ProcessingTimeMs)
 
 		// Component 3: Intent Classification
 		span.AddEvent("component_3_intent_classification")
@@ -287,9 +288,9 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		if err != nil {
 			return fmt.Errorf("intent classification failed: %w", err)
 		}
-		span.SetAttribute("intent.name", intentResp.Intent)
+		span.SetAttributeNonZero("intent.name", intentResp.Intent)
 		span.SetAttribute("intent.confidence", intentResp.Confidence)
-		totalTime += intentResp.ProcessingTimeMs
+		work.Add("pipeline.total_time_ms", intentResp.ProcessingTimeMs)
 
 		// Component 4: Knowledge Search
 		span.AddEvent("component_4_knowledge_search")
@@ -304,8 +305,8 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 			return fmt.Errorf("knowledge search failed: %w", err)
 		}
 		span.SetAttribute("search.result_count", searchResp.ResultCount)
-		span.SetAttribute("search.avg_relevance", searchResp.AvgRelevance)
-		totalTime += searchResp.ProcessingTimeMs
+		span.SetAttributeIf(searchResp.ResultCount > 0, "search.avg_relevance", searchResp.AvgRelevance)
+		work.Add("pipeline.total_time_ms", searchResp.ProcessingTimeMs)
 
 		// Component 5: Response Generation
 		span.AddEvent("component_5_response_generation")
@@ -323,8 +324,8 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 			return fmt.Errorf("response generation failed: %w", err)
 		}
 		span.SetAttribute("llm.tokens_total", responseResp.TokensTotal)
-		span.SetAttribute("llm.finish_reason", responseResp.FinishReason)
-		totalTime += responseResp.ProcessingTimeMs
+		span.SetAttributeNonZero("llm.finish_reason", responseResp.FinishReason)
+		work.Add("pipeline.total_time_ms", responseResp.ProcessingTimeMs)
 
 		// Component 6: Response Validation
 		span.AddEvent("component_6_response_validation")
@@ -338,7 +339,7 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		}
 		span.SetAttribute("validation.is_valid", validationResp.IsValid)
 		span.SetAttribute("validation.is_safe", validationResp.IsSafe)
-		totalTime += validationResp.ProcessingTimeMs
+		work.Add("pipeline.total_time_ms", validationResp.ProcessingTimeMs)
 
 		// Component 7: Output Formatting
 		span.AddEvent("component_7_output_formatting")
@@ -352,10 +353,9 @@ func processMaleniaConversation(ctx context.Context, k *pulse.Pulse, req Convers
 		}
 		span.SetAttribute("output.length", outputResp.OutputLength)
 		span.SetAttribute("output.markdown", outputResp.Markdown)
-		totalTime += outputResp.ProcessingTimeMs
+		work.Add("pipeline.total_time_ms", outputResp.ProcessingTimeMs)
 
 		span.AddEvent("conversation_completed")
-		span.SetAttribute("pipeline.total_time_ms", totalTime)
 		span.SetAttribute("pipeline.components_completed", 7)
 		span.SetAttribute("pipeline.success", true)
 
@@ -368,17 +368,10 @@ func processInput(ctx context.Context, k *pulse.Pulse, req InputProcessingReques
 	_, span := k.Tracing.Start(ctx, "InputProcessing", req)
 	defer span.End()
 
-	span.AddEvent("validating_input")
-	time.Sleep(15 * time.Millisecond)
-
-	span.AddEvent("normalizing_text")
-	time.Sleep(20 * time.Millisecond)
-
-	span.AddEvent("detecting_language")
-	time.Sleep(10 * time.Millisecond)
-
-	span.AddEvent("tokenizing")
-	time.Sleep(25 * time.Millisecond)
+	span.Timed("validating_input", func() { time.Sleep(15 * time.Millisecond) })
+	span.Timed("normalizing_text", func() { time.Sleep(20 * time.Millisecond) })
+	span.Timed("detecting_language", func() { time.Sleep(10 * time.Millisecond) })
+	span.Timed("tokenizing", func() { time.Sleep(25 * time.Millisecond) })
 
 	response := &InputProcessingResponse{
 		RequestID:        req.RequestID,
@@ -400,17 +393,10 @@ func retrieveContext(ctx context.Context, k *pulse.Pulse, req ContextRetrievalRe
 	_, span := k.Tracing.Start(ctx, "ContextRetrieval", req)
 	defer span.End()
 
-	span.AddEvent("checking_cache")
-	time.Sleep(5 * time.Millisecond)
-
-	span.AddEvent("fetching_conversation_history")
-	time.Sleep(40 * time.Millisecond)
-
-	span.AddEvent("loading_user_preferences")
-	time.Sleep(30 * time.Millisecond)
-
-	span.AddEvent("aggregating_context")
-	time.Sleep(15 * time.Millisecond)
+	span.Timed("checking_cache", func() { time.Sleep(5 * time.Millisecond) })
+	span.Timed("fetching_conversation_history", func() { time.Sleep(40 * time.Millisecond) })
+	span.Timed("loading_user_preferences", func() { time.Sleep(30 * time.Millisecond) })
+	span.Timed("aggregating_context", func() { time.Sleep(15 * time.Millisecond) })
 
 	response := &ContextRetrievalResponse{
 		RequestID:        req.RequestID,
@@ -432,17 +418,10 @@ func classifyIntent(ctx context.Context, k *pulse.Pulse, req IntentClassificatio
 	_, span := k.Tracing.Start(ctx, "IntentClassification", req)
 	defer span.End()
 
-	span.AddEvent("loading_classifier_model")
-	time.Sleep(20 * time.Millisecond)
-
-	span.AddEvent("extracting_features")
-	time.Sleep(30 * time.Millisecond)
-
-	span.AddEvent("running_classification")
-	time.Sleep(50 * time.Millisecond)
-
-	span.AddEvent("extracting_entities")
-	time.Sleep(35 * time.Millisecond)
+	span.Timed("loading_classifier_model", func() { time.Sleep(20 * time.Millisecond) })
+	span.Timed("extracting_features", func() { time.Sleep(30 * time.Millisecond) })
+	span.Timed("running_classification", func() { time.Sleep(50 * time.Millisecond) })
+	span.Timed("extracting_entities", func() { time.Sleep(35 * time.Millisecond) })
 
 	response := &IntentClassificationResponse{
 		RequestID:        req.RequestID,
@@ -464,17 +443,10 @@ func searchKnowledge(ctx context.Context, k *pulse.Pulse, req KnowledgeSearchReq
 	_, span := k.Tracing.Start(ctx, "KnowledgeSearch", req)
 	defer span.End()
 
-	span.AddEvent("generating_query_embedding")
-	time.Sleep(45 * time.Millisecond)
-
-	span.AddEvent("searching_vector_index")
-	time.Sleep(120 * time.Millisecond)
-
-	span.AddEvent("filtering_by_relevance")
-	time.Sleep(20 * time.Millisecond)
-
-	span.AddEvent("ranking_results")
-	time.Sleep(30 * time.Millisecond)
+	span.Timed("generating_query_embedding", func() { time.Sleep(45 * time.Millisecond) })
+	span.Timed("searching_vector_index", func() { time.Sleep(120 * time.Millisecond) })
+	span.Timed("filtering_by_relevance", func() { time.Sleep(20 * time.Millisecond) })
+	span.Timed("ranking_results", func() { time.Sleep(30 * time.Millisecond) })
 
 	response := &KnowledgeSearchResponse{
 		RequestID:        req.RequestID,
@@ -496,20 +468,11 @@ func generateResponse(ctx context.Context, k *pulse.Pulse, req ResponseGeneratio
 	_, span := k.Tracing.Start(ctx, "ResponseGeneration", req)
 	defer span.End()
 
-	span.AddEvent("preparing_prompt")
-	time.Sleep(20 * time.Millisecond)
-
-	span.AddEvent("tokenizing_input")
-	time.Sleep(25 * time.Millisecond)
-
-	span.AddEvent("calling_llm_api")
-	time.Sleep(180 * time.Millisecond)
-
-	span.AddEvent("parsing_response")
-	time.Sleep(15 * time.Millisecond)
-
-	span.AddEvent("detokenizing_output")
-	time.Sleep(10 * time.Millisecond)
+	span.Timed("preparing_prompt", func() { time.Sleep(20 * time.Millisecond) })
+	span.Timed("tokenizing_input", func() { time.Sleep(25 * time.Millisecond) })
+	span.Timed("calling_llm_api", func() { time.Sleep(180 * time.Millisecond) })
+	span.Timed("parsing_response", func() { time.Sleep(15 * time.Millisecond) })
+	span.Timed("detokenizing_output", func() { time.Sleep(10 * time.Millisecond) })
 
 	response := &ResponseGenerationResponse{
 		RequestID:        req.RequestID,
@@ -532,17 +495,10 @@ func validateResponse(ctx context.Context, k *pulse.Pulse, req ResponseValidatio
 	_, span := k.Tracing.Start(ctx, "ResponseValidation", req)
 	defer span.End()
 
-	span.AddEvent("checking_content_safety")
-	time.Sleep(40 * time.Millisecond)
-
-	span.AddEvent("detecting_pii")
-	time.Sleep(30 * time.Millisecond)
-
-	span.AddEvent("calculating_toxicity_score")
-	time.Sleep(25 * time.Millisecond)
-
-	span.AddEvent("validating_format")
-	time.Sleep(15 * time.Millisecond)
+	span.Timed("checking_content_safety", func() { time.Sleep(40 * time.Millisecond) })
+	span.Timed("detecting_pii", func() { time.Sleep(30 * time.Millisecond) })
+	span.Timed("calculating_toxicity_score", func() { time.Sleep(25 * time.Millisecond) })
+	span.Timed("validating_format", func() { time.Sleep(15 * time.Millisecond) })
 
 	response := &ResponseValidationResponse{
 		RequestID:        req.RequestID,
@@ -564,17 +520,10 @@ func formatOutput(ctx context.Context, k *pulse.Pulse, req OutputFormattingReque
 	_, span := k.Tracing.Start(ctx, "OutputFormatting", req)
 	defer span.End()
 
-	span.AddEvent("applying_markdown_formatting")
-	time.Sleep(20 * time.Millisecond)
-
-	span.AddEvent("adding_citations")
-	time.Sleep(15 * time.Millisecond)
-
-	span.AddEvent("adding_metadata")
-	time.Sleep(10 * time.Millisecond)
-
-	span.AddEvent("finalizing_output")
-	time.Sleep(10 * time.Millisecond)
+	span.Timed("applying_markdown_formatting", func() { time.Sleep(20 * time.Millisecond) })
+	span.Timed("adding_citations", func() { time.Sleep(15 * time.Millisecond) })
+	span.Timed("adding_metadata", func() { time.Sleep(10 * time.Millisecond) })
+	span.Timed("finalizing_output", func() { time.Sleep(10 * time.Millisecond) })
 
 	response := &OutputFormattingResponse{
 		RequestID:        req.RequestID,
@@ -0,0 +1,126 @@
+package foxglove
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/foxglove/mcap/go/mcap"
+)
+
+// chunkSize is the target size of the compressed chunks of MCAP files
+const chunkSize = 1024 * 1024
+
+// flushTopic is the channel of the empty messages that close the chunk of an idle writer
+const flushTopic = "/pulse/flush"
+
+// writeMessage writes a message to the current chunk. Once FoxgloveOptions.FlushIntervalSeconds have
+// passed since the last flush, the chunk is closed with this message and the file is synced to disk,
+// so a process killed without Close loses at most the messages of about one interval.
+//
+// The MCAP writer buffers the open chunk in memory and has no flush method; it closes a chunk when it
+// grows past ChunkSize after a message, so ChunkSize is set to 0 for that one message. A writer that
+// goes idle is flushed by the flusher goroutine instead (see startFlusher). A file cut off this way
+// has no summary section; `mcap recover` rebuilds it.
+func (u *UnifiedMcapWriter) writeMessage(msg *mcap.Message) error {
+	if u.flushInterval <= 0 || time.Since(u.lastFlush) < u.flushInterval {
+		u.pending = true
+		return u.writer.WriteMessage(msg)
+	}
+	return u.flushWith(msg)
+}
+
+// flushWith writes msg as the last message of the current chunk, closes the chunk and syncs the file.
+// The caller holds u.mu.
+func (u *UnifiedMcapWriter) flushWith(msg *mcap.Message) error {
+	u.chunking.ChunkSize = 0
+	err := u.writer.WriteMessage(msg)
+	u.chunking.ChunkSize = chunkSize
+	u.lastFlush = time.Now()
+	u.pending = false
+	if err != nil {
+		return err
+	}
+
+	if err := u.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync MCAP file: %w", err)
+	}
+	return nil
+}
+
+// startFlusher starts the goroutine that flushes an idle writer every FoxgloveOptions.FlushIntervalSeconds,
+// so messages written just before the writer went idle reach the disk too. It is stopped by Close.
+func (u *UnifiedMcapWriter) startFlusher() {
+	if u.flushInterval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	u.stopFlush = stop
+	go func() {
+		ticker := time.NewTicker(u.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = u.flushIdle() // Ignore errors, the next message or Close reports them
+			}
+		}
+	}()
+}
+
+// stopFlusher stops the flusher goroutine if it is running. The caller holds u.mu.
+func (u *UnifiedMcapWriter) stopFlusher() {
+	if u.stopFlush != nil {
+		close(u.stopFlush)
+		u.stopFlush = nil
+	}
+}
+
+// flushIdle closes the current chunk if messages were written to it and no flush happened for an interval.
+// Since the chunk can only be closed after a message, an empty message is written on flushTopic.
+func (u *UnifiedMcapWriter) flushIdle() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed || !u.pending || time.Since(u.lastFlush) < u.flushInterval {
+		return nil
+	}
+
+	channelID, err := u.flushChannel()
+	if err != nil {
+		return err
+	}
+	u.sequences[channelID]++
+	now := uint64(u.clock.Now().UnixNano())
+	return u.flushWith(&mcap.Message{
+		ChannelID:   channelID,
+		Sequence:    u.sequences[channelID],
+		LogTime:     now,
+		PublishTime: now,
+		Data:        []byte("{}"),
+	})
+}
+
+// flushChannel returns the channel of flushTopic, writing it on first use. The channel has no schema
+// and is not advertised to the live stream. The caller holds u.mu.
+func (u *UnifiedMcapWriter) flushChannel() (uint16, error) {
+	if channelID, exists := u.channels[flushTopic]; exists {
+		return channelID, nil
+	}
+
+	channel := &mcap.Channel{
+		ID:              u.nextChannel,
+		Topic:           flushTopic,
+		MessageEncoding: "json",
+	}
+	if err := u.writer.WriteChannel(channel); err != nil {
+		return 0, fmt.Errorf("failed to create flush channel: %w", err)
+	}
+
+	u.records = append(u.records, channel)
+	u.channels[flushTopic] = channel.ID
+	u.nextChannel++
+	return channel.ID, nil
+}
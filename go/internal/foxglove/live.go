@@ -0,0 +1,277 @@
+package foxglove
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// liveSubprotocol is the WebSocket subprotocol spoken by Foxglove Studio
+// https://github.com/foxglove/ws-protocol/blob/main/docs/spec.md
+const liveSubprotocol = "foxglove.websocket.v1"
+
+// liveOpMessageData is the opcode of binary message data frames
+const liveOpMessageData byte = 0x01
+
+// liveClientQueueSize bounds the frames buffered per client; frames are dropped for slow clients
+const liveClientQueueSize = 1024
+
+// LiveServer streams MCAP channels to Foxglove Studio over the foxglove-websocket protocol.
+// Channels are advertised with the same schemas as the MCAP file, and messages are sent to
+// clients subscribed to their channel as they are written.
+type LiveServer struct {
+	name     string
+	listener net.Listener
+	server   *http.Server
+
+	mu       sync.Mutex
+	channels map[uint16]liveChannel
+	clients  map[*liveClient]struct{}
+}
+
+// liveChannel is a channel advertised to clients
+type liveChannel struct {
+	ID             uint16 `json:"id"`
+	Topic          string `json:"topic"`
+	Encoding       string `json:"encoding"`
+	SchemaName     string `json:"schemaName"`
+	Schema         string `json:"schema"`
+	SchemaEncoding string `json:"schemaEncoding"`
+}
+
+// liveClient is a connected Foxglove Studio instance
+type liveClient struct {
+	conn   *websocket.Conn
+	frames chan liveFrame
+	mu     sync.Mutex
+	subs   map[uint16]uint32 // channel ID -> subscription ID
+}
+
+// liveFrame is a text (JSON) or binary frame queued for a client
+type liveFrame struct {
+	data   []byte
+	binary bool
+}
+
+// NewLiveServer starts a live stream server listening on address (e.g., ":8765")
+func NewLiveServer(name, address string) (*LiveServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	s := &LiveServer{
+		name:     name,
+		listener: listener,
+		channels: make(map[uint16]liveChannel),
+		clients:  make(map[*liveClient]struct{}),
+	}
+	s.server = &http.Server{Handler: websocket.Server{
+		Handshake: s.handshake,
+		Handler:   s.handle,
+	}}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Warning: Foxglove live stream stopped: %v\n", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *LiveServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Advertise makes a channel available to current and future clients
+func (s *LiveServer) Advertise(channelID uint16, topic, schemaName, schema string) {
+	channel := liveChannel{
+		ID:             channelID,
+		Topic:          topic,
+		Encoding:       "json",
+		SchemaName:     schemaName,
+		Schema:         schema,
+		SchemaEncoding: "jsonschema",
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[channelID] = channel
+	msg := advertiseMessage([]liveChannel{channel})
+	for c := range s.clients {
+		c.send(liveFrame{data: msg})
+	}
+}
+
+// Publish sends a message to the clients subscribed to its channel
+func (s *LiveServer) Publish(channelID uint16, logTime uint64, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		c.mu.Lock()
+		subID, subscribed := c.subs[channelID]
+		c.mu.Unlock()
+		if !subscribed {
+			continue
+		}
+
+		// opcode (1) + subscription ID (4) + timestamp (8) + payload
+		frame := make([]byte, 13+len(data))
+		frame[0] = liveOpMessageData
+		binary.LittleEndian.PutUint32(frame[1:5], subID)
+		binary.LittleEndian.PutUint64(frame[5:13], logTime)
+		copy(frame[13:], data)
+		c.send(liveFrame{data: frame, binary: true})
+	}
+}
+
+// Close stops the server and disconnects all clients
+func (s *LiveServer) Close() error {
+	s.mu.Lock()
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+	s.mu.Unlock()
+
+	return s.server.Close()
+}
+
+// handshake accepts clients that speak the foxglove-websocket subprotocol
+func (s *LiveServer) handshake(config *websocket.Config, _ *http.Request) error {
+	for _, protocol := range config.Protocol {
+		if protocol == liveSubprotocol {
+			config.Protocol = []string{liveSubprotocol}
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported subprotocol %v, expected %s", config.Protocol, liveSubprotocol)
+}
+
+// handle serves a connected client until it disconnects
+func (s *LiveServer) handle(conn *websocket.Conn) {
+	c := &liveClient{
+		conn:   conn,
+		frames: make(chan liveFrame, liveClientQueueSize),
+		subs:   make(map[uint16]uint32),
+	}
+
+	// Greet the client and advertise the existing channels
+	s.mu.Lock()
+	c.send(liveFrame{data: s.serverInfoMessage()})
+	channels := make([]liveChannel, 0, len(s.channels))
+	for _, channel := range s.channels {
+		channels = append(channels, channel)
+	}
+	c.send(liveFrame{data: advertiseMessage(channels)})
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go c.writeLoop(done)
+
+	c.readLoop()
+
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+	close(done)
+}
+
+// serverInfoMessage returns the serverInfo message sent on connect
+func (s *LiveServer) serverInfoMessage() []byte {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"op":                 "serverInfo",
+		"name":               s.name,
+		"capabilities":       []string{},
+		"supportedEncodings": []string{},
+		"metadata":           map[string]string{},
+	})
+	return msg
+}
+
+// advertiseMessage returns an advertise message for the given channels
+func advertiseMessage(channels []liveChannel) []byte {
+	msg, _ := json.Marshal(map[string]interface{}{
+		"op":       "advertise",
+		"channels": channels,
+	})
+	return msg
+}
+
+// send queues a frame, dropping it if the client is too slow
+func (c *liveClient) send(frame liveFrame) {
+	select {
+	case c.frames <- frame:
+	default:
+	}
+}
+
+// writeLoop writes queued frames to the connection until done is closed
+func (c *liveClient) writeLoop(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case frame := <-c.frames:
+			var err error
+			if frame.binary {
+				err = websocket.Message.Send(c.conn, frame.data)
+			} else {
+				err = websocket.Message.Send(c.conn, string(frame.data))
+			}
+			if err != nil {
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// readLoop handles subscribe/unsubscribe requests until the connection is closed
+func (c *liveClient) readLoop() {
+	for {
+		var raw string
+		if err := websocket.Message.Receive(c.conn, &raw); err != nil {
+			return
+		}
+
+		var req struct {
+			Op            string `json:"op"`
+			Subscriptions []struct {
+				ID        uint32 `json:"id"`
+				ChannelID uint16 `json:"channelId"`
+			} `json:"subscriptions"`
+			SubscriptionIDs []uint32 `json:"subscriptionIds"`
+		}
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			continue // Ignore malformed or unsupported requests
+		}
+
+		c.mu.Lock()
+		switch req.Op {
+		case "subscribe":
+			for _, sub := range req.Subscriptions {
+				c.subs[sub.ChannelID] = sub.ID
+			}
+		case "unsubscribe":
+			for _, id := range req.SubscriptionIDs {
+				for channelID, subID := range c.subs {
+					if subID == id {
+						delete(c.subs, channelID)
+					}
+				}
+			}
+		}
+		c.mu.Unlock()
+	}
+}
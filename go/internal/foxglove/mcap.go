@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/foxglove/mcap/go/mcap"
 	"github.com/machanirobotics/pulse/go/options"
@@ -13,11 +14,33 @@ import (
 // UnifiedMcapWriter manages a single MCAP file with multiple schemas and channels
 // for both logging and metrics
 type UnifiedMcapWriter struct {
-	writer   *mcap.Writer
+	writer *mcap.Writer
+
+	// Options of writer; ChunkSize is lowered to close a chunk early (see writeMessage). This relies on
+	// mcap.Writer keeping the options pointer and reading ChunkSize after every message, which is an
+	// implementation detail of the library rather than part of its API; check it when upgrading mcap.
+	chunking *mcap.WriterOptions
+
 	file     *os.File
 	mu       sync.Mutex
 	filePath string
+	profile  string // Header profile (service name), reused by Rotate
 	closed   bool
+	opts     options.FoxgloveOptions
+	clock    options.Clock // Time source of log and metric timestamps
+
+	// Sharing between Pulse instances (see NewSharedUnifiedMcapWriter)
+	shared bool
+	refs   int
+
+	// Periodic flush of the current chunk (FoxgloveOptions.FlushIntervalSeconds, 0 disables)
+	flushInterval time.Duration
+	lastFlush     time.Time
+	pending       bool          // Messages were written since the last flush
+	stopFlush     chan struct{} // Stops the flusher goroutine (nil if not running)
+
+	// Live stream to Foxglove Studio (nil if disabled)
+	live *LiveServer
 
 	// Schema management
 	registry     *SchemaRegistry
@@ -27,6 +50,10 @@ type UnifiedMcapWriter struct {
 	// Channel tracking
 	channels    map[string]uint16 // topic -> channel ID
 	nextChannel uint16
+	sequences   map[uint16]uint32 // channel ID -> sequence number of the last message
+
+	// Records written so far, rewritten to the next file by Rotate (in the original order)
+	records []any // *mcap.Schema, *mcap.Channel or *mcap.Metadata
 }
 
 // NewUnifiedMcapWriter creates a unified MCAP writer for logs and metrics
@@ -47,50 +74,124 @@ func NewUnifiedMcapWriter(serviceOpts options.ServiceOptions, foxgloveOpts optio
 		return nil, fmt.Errorf("failed to create MCAP file: %w", err)
 	}
 
-	// Create MCAP writer
-	writer, err := mcap.NewWriter(file, &mcap.WriterOptions{
+	writer, chunking, err := newMcapFileWriter(file, serviceOpts.Name, foxgloveOpts.Compression)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	unified := &UnifiedMcapWriter{
+		writer:        writer,
+		chunking:      chunking,
+		flushInterval: time.Duration(foxgloveOpts.FlushIntervalSeconds) * time.Second,
+		lastFlush:     time.Now(),
+		file:          file,
+		filePath:      foxgloveOpts.McapPath,
+		profile:       serviceOpts.Name,
+		opts:          foxgloveOpts,
+		clock:         resolveClock(foxgloveOpts.Clock),
+		registry:      NewSchemaRegistry(),
+		schemaIDs:     make(map[string]uint16),
+		channels:      make(map[string]uint16),
+		sequences:     make(map[uint16]uint32),
+		nextSchemaID:  1,
+		nextChannel:   1,
+	}
+
+	// Register built-in schemas
+	if err := unified.registerBuiltInSchemas(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// Start the live stream server if enabled
+	if foxgloveOpts.LiveStream.Enabled {
+		address := foxgloveOpts.LiveStream.Address
+		if address == "" {
+			address = defaultLiveStreamAddress
+		}
+		live, err := NewLiveServer(serviceOpts.Name, address)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		unified.live = live
+	}
+
+	unified.startFlusher()
+	return unified, nil
+}
+
+// newMcapFileWriter creates the MCAP writer of a new file and writes its header.
+// The writer options are returned too, since the writer keeps using them (see writeMessage).
+func newMcapFileWriter(file *os.File, profile string, compression options.McapCompression) (*mcap.Writer, *mcap.WriterOptions, error) {
+	chunking := &mcap.WriterOptions{
 		Chunked:     true,
-		ChunkSize:   1024 * 1024,
-		Compression: mcap.CompressionZSTD,
+		ChunkSize:   chunkSize,
+		Compression: resolveCompression(compression),
 		IncludeCRC:  true,
-	})
+	}
+	writer, err := mcap.NewWriter(file, chunking)
 	if err != nil {
-		_ = file.Close()
-		return nil, fmt.Errorf("failed to create MCAP writer: %w", err)
+		return nil, nil, fmt.Errorf("failed to create MCAP writer: %w", err)
 	}
 
 	// Write header
 	if err := writer.WriteHeader(&mcap.Header{
-		Profile: serviceOpts.Name,
+		Profile: profile,
 		Library: "github.com/machanirobotics/pulse/go/",
 	}); err != nil {
-		_ = file.Close()
-		return nil, fmt.Errorf("failed to write header: %w", err)
+		return nil, nil, fmt.Errorf("failed to write header: %w", err)
 	}
+	return writer, chunking, nil
+}
 
-	unified := &UnifiedMcapWriter{
-		writer:       writer,
-		file:         file,
-		filePath:     foxgloveOpts.McapPath,
-		registry:     NewSchemaRegistry(),
-		schemaIDs:    make(map[string]uint16),
-		channels:     make(map[string]uint16),
-		nextSchemaID: 1,
-		nextChannel:  1,
+// defaultLiveStreamAddress is the default listen address of the live stream (Foxglove's default port)
+const defaultLiveStreamAddress = ":8765"
+
+// resolveCompression maps the configured compression to the MCAP compression format (default: zstd)
+func resolveCompression(compression options.McapCompression) mcap.CompressionFormat {
+	switch compression {
+	case options.McapCompressionLZ4:
+		return mcap.CompressionLZ4
+	case options.McapCompressionNone:
+		return mcap.CompressionNone
+	default:
+		return mcap.CompressionZSTD
 	}
+}
 
-	// Register built-in schemas
-	if err := unified.registerBuiltInSchemas(); err != nil {
-		_ = file.Close()
-		return nil, err
+// realClock is the default Clock, reading the system time
+type realClock struct{}
+
+// Now returns the current system time
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// resolveClock returns the configured clock, or the system clock if none is set
+func resolveClock(clock options.Clock) options.Clock {
+	if clock == nil {
+		return realClock{}
 	}
+	return clock
+}
 
+// NewSharedUnifiedMcapWriter creates a unified MCAP writer that several Pulse instances can write to.
+// Each instance takes a reference with Acquire and gives it back with Release; the file is closed
+// when the last reference is released.
+func NewSharedUnifiedMcapWriter(serviceOpts options.ServiceOptions, foxgloveOpts options.FoxgloveOptions) (*UnifiedMcapWriter, error) {
+	unified, err := NewUnifiedMcapWriter(serviceOpts, foxgloveOpts)
+	if err != nil {
+		return nil, err
+	}
+	unified.shared = true
 	return unified, nil
 }
 
-// registerBuiltInSchemas registers the built-in schemas (foxglove.Log and mahcanirobotics.metric)
+// registerBuiltInSchemas registers the built-in schemas (foxglove.Log, mahcanirobotics.metric and mahcanirobotics.span_interval)
 func (u *UnifiedMcapWriter) registerBuiltInSchemas() error {
-	for _, schemaName := range []string{"foxglove.Log", "mahcanirobotics.metric"} {
+	for _, schemaName := range []string{"foxglove.Log", "mahcanirobotics.metric", "mahcanirobotics.span_interval"} {
 		if err := u.RegisterSchema(schemaName); err != nil {
 			return err
 		}
@@ -116,15 +217,17 @@ func (u *UnifiedMcapWriter) RegisterSchema(schemaName string) error {
 
 	// Assign schema ID and write to MCAP
 	schemaID := u.nextSchemaID
-	if err := u.writer.WriteSchema(&mcap.Schema{
+	schema := &mcap.Schema{
 		ID:       schemaID,
 		Name:     schemaName,
 		Encoding: "jsonschema",
 		Data:     []byte(schemaData),
-	}); err != nil {
+	}
+	if err := u.writer.WriteSchema(schema); err != nil {
 		return fmt.Errorf("failed to write schema %s: %w", schemaName, err)
 	}
 
+	u.records = append(u.records, schema)
 	u.schemaIDs[schemaName] = schemaID
 	u.nextSchemaID++
 	return nil
@@ -146,6 +249,11 @@ func (u *UnifiedMcapWriter) CreateMetricChannel(topic string, metadata map[strin
 	return u.CreateChannel(topic, "mahcanirobotics.metric", metadata)
 }
 
+// CreateSpanChannel creates a channel for span timeline intervals using the mahcanirobotics.span_interval schema
+func (u *UnifiedMcapWriter) CreateSpanChannel(topic string, metadata map[string]string) (uint16, error) {
+	return u.CreateChannel(topic, "mahcanirobotics.span_interval", metadata)
+}
+
 // CreateChannel creates a channel with a specific schema
 func (u *UnifiedMcapWriter) CreateChannel(topic, schemaName string, metadata map[string]string) (uint16, error) {
 	u.mu.Lock()
@@ -164,35 +272,73 @@ func (u *UnifiedMcapWriter) CreateChannel(topic, schemaName string, metadata map
 
 	// Create channel
 	channelID := u.nextChannel
-	if err := u.writer.WriteChannel(&mcap.Channel{
+	channel := &mcap.Channel{
 		ID:              channelID,
 		SchemaID:        schemaID,
 		Topic:           topic,
 		MessageEncoding: "json",
 		Metadata:        metadata,
-	}); err != nil {
+	}
+	if err := u.writer.WriteChannel(channel); err != nil {
 		return 0, fmt.Errorf("failed to create channel: %w", err)
 	}
 
+	u.records = append(u.records, channel)
 	u.channels[topic] = channelID
 	u.nextChannel++
+
+	// Advertise the channel to live stream clients with the same schema
+	if u.live != nil {
+		schemaData, _ := u.registry.Get(schemaName)
+		u.live.Advertise(channelID, topic, schemaName, schemaData)
+	}
+
 	return channelID, nil
 }
 
-// WriteMessage writes a message to a specific channel
+// WriteMessage writes a message to a specific channel.
+// Messages are numbered per channel starting at 1 (the MCAP sequence field), so gaps reveal dropped
+// messages during playback; numbering continues across Rotate.
 func (u *UnifiedMcapWriter) WriteMessage(channelID uint16, data []byte, logTime, publishTime uint64) error {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	return u.writer.WriteMessage(&mcap.Message{
+	u.sequences[channelID]++
+
+	if u.live != nil {
+		u.live.Publish(channelID, logTime, data)
+	}
+
+	return u.writeMessage(&mcap.Message{
 		ChannelID:   channelID,
-		Sequence:    0,
+		Sequence:    u.sequences[channelID],
 		LogTime:     logTime,
 		PublishTime: publishTime,
 		Data:        data,
 	})
 }
 
+// WriteMetadata writes a metadata record (e.g., the service resource) so the recording is self-describing.
+// Several records with the same name are allowed, e.g. one per service sharing the file.
+func (u *UnifiedMcapWriter) WriteMetadata(name string, metadata map[string]string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return fmt.Errorf("MCAP writer is closed")
+	}
+
+	record := &mcap.Metadata{
+		Name:     name,
+		Metadata: metadata,
+	}
+	if err := u.writer.WriteMetadata(record); err != nil {
+		return err
+	}
+	u.records = append(u.records, record)
+	return nil
+}
+
 // Close closes the MCAP writer
 func (u *UnifiedMcapWriter) Close() error {
 	u.mu.Lock()
@@ -201,6 +347,11 @@ func (u *UnifiedMcapWriter) Close() error {
 	if u.closed {
 		return nil
 	}
+	u.stopFlusher()
+
+	if u.live != nil {
+		_ = u.live.Close() // Clients are disconnected, the file still needs closing
+	}
 
 	if err := u.writer.Close(); err != nil {
 		_ = u.file.Close()
@@ -215,6 +366,38 @@ func (u *UnifiedMcapWriter) Close() error {
 	return nil
 }
 
+// Acquire takes a reference to a shared writer
+func (u *UnifiedMcapWriter) Acquire() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return fmt.Errorf("MCAP writer %s is closed", u.filePath)
+	}
+	u.refs++
+	return nil
+}
+
+// Release gives back a reference taken with Acquire and closes the writer once no references remain.
+// For writers that are not shared, Release is the same as Close.
+func (u *UnifiedMcapWriter) Release() error {
+	u.mu.Lock()
+	if u.shared && u.refs > 1 {
+		u.refs--
+		u.mu.Unlock()
+		return nil
+	}
+	u.refs = 0
+	u.mu.Unlock()
+
+	return u.Close()
+}
+
+// IsShared returns whether the writer is shared by several Pulse instances
+func (u *UnifiedMcapWriter) IsShared() bool {
+	return u.shared
+}
+
 // IsClosed returns whether the writer is closed
 func (u *UnifiedMcapWriter) IsClosed() bool {
 	u.mu.Lock()
@@ -222,7 +405,19 @@ func (u *UnifiedMcapWriter) IsClosed() bool {
 	return u.closed
 }
 
-// GetFilePath returns the path to the MCAP file
+// GetFilePath returns the path to the MCAP file (the current file after Rotate)
 func (u *UnifiedMcapWriter) GetFilePath() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 	return u.filePath
 }
+
+// Now returns the current time of the writer's clock (FoxgloveOptions.Clock)
+func (u *UnifiedMcapWriter) Now() time.Time {
+	return u.clock.Now()
+}
+
+// Options returns the Foxglove options the writer was created with
+func (u *UnifiedMcapWriter) Options() options.FoxgloveOptions {
+	return u.opts
+}
@@ -0,0 +1,169 @@
+package foxglove
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/foxglove/mcap/go/mcap"
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+// fixedClock always returns the same time, so rotated file names are predictable
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// newTestWriter creates a writer recording to pulse.mcap in a temporary directory
+func newTestWriter(t *testing.T, opts options.FoxgloveOptions) *UnifiedMcapWriter {
+	t.Helper()
+
+	opts.McapPath = filepath.Join(t.TempDir(), "pulse.mcap")
+	if opts.Clock == nil {
+		opts.Clock = fixedClock{t: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)}
+	}
+	writer, err := NewUnifiedMcapWriter(options.ServiceOptions{Name: "test"}, opts)
+	if err != nil {
+		t.Fatalf("NewUnifiedMcapWriter() error = %v", err)
+	}
+	t.Cleanup(func() { _ = writer.Close() })
+	return writer
+}
+
+// readMessages returns the topics and data of the messages in an MCAP file, which need not be finished
+func readMessages(t *testing.T, path string) map[string][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	reader, err := mcap.NewReader(file)
+	if err != nil {
+		t.Fatalf("mcap.NewReader(%s) error = %v", path, err)
+	}
+	it, err := reader.Messages(mcap.UsingIndex(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := make(map[string][]string)
+	for {
+		_, channel, msg, err := it.NextInto(nil)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return messages
+		}
+		if err != nil {
+			t.Fatalf("reading %s: %v", path, err)
+		}
+		messages[channel.Topic] = append(messages[channel.Topic], string(msg.Data))
+	}
+}
+
+func TestRotate(t *testing.T) {
+	writer := newTestWriter(t, options.FoxgloveOptions{})
+	first := writer.GetFilePath()
+
+	channel, err := writer.CreateLogChannel("/logs", nil)
+	if err != nil {
+		t.Fatalf("CreateLogChannel() error = %v", err)
+	}
+	if err := writer.WriteMessage(channel, []byte(`{"message":"before"}`), 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{"pulse-20260102-150405.mcap", "pulse-20260102-150405-2.mcap"} {
+		if err := writer.Rotate(); err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+		if got := filepath.Base(writer.GetFilePath()); got != want {
+			t.Errorf("rotation %d wrote to %s, want %s", i+1, got, want)
+		}
+	}
+
+	// The existing channel keeps working in the new file
+	if err := writer.WriteMessage(channel, []byte(`{"message":"after"}`), 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	last := writer.GetFilePath()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := readMessages(t, first)["/logs"]; len(got) != 1 || got[0] != `{"message":"before"}` {
+		t.Errorf("first file messages = %v, want the message written before Rotate", got)
+	}
+	if got := readMessages(t, last)["/logs"]; len(got) != 1 || got[0] != `{"message":"after"}` {
+		t.Errorf("rotated file messages = %v, want the message written after Rotate", got)
+	}
+	if err := writer.Rotate(); err == nil {
+		t.Error("Rotate() after Close succeeded")
+	}
+}
+
+func TestFlushIdle(t *testing.T) {
+	writer := newTestWriter(t, options.FoxgloveOptions{FlushIntervalSeconds: 60})
+
+	channel, err := writer.CreateLogChannel("/logs", nil)
+	if err != nil {
+		t.Fatalf("CreateLogChannel() error = %v", err)
+	}
+	if err := writer.WriteMessage(channel, []byte(`{"message":"buffered"}`), 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := readMessages(t, writer.GetFilePath())["/logs"]; len(got) != 0 {
+		t.Fatalf("messages on disk before the flush = %v, want none (chunk still open)", got)
+	}
+
+	// Not idle for an interval yet
+	if err := writer.flushIdle(); err != nil {
+		t.Fatalf("flushIdle() error = %v", err)
+	}
+	if !writer.pending {
+		t.Fatal("flushIdle() flushed before the interval passed")
+	}
+
+	writer.mu.Lock()
+	writer.lastFlush = time.Now().Add(-2 * writer.flushInterval)
+	writer.mu.Unlock()
+	if err := writer.flushIdle(); err != nil {
+		t.Fatalf("flushIdle() error = %v", err)
+	}
+
+	messages := readMessages(t, writer.GetFilePath())
+	if got := messages["/logs"]; len(got) != 1 {
+		t.Errorf("messages on disk after the flush = %v, want the buffered message", got)
+	}
+	if got := messages[flushTopic]; len(got) != 1 || got[0] != "{}" {
+		t.Errorf("flush messages = %v, want one empty message on %s", got, flushTopic)
+	}
+
+	// Nothing new to flush
+	if err := writer.flushIdle(); err != nil {
+		t.Fatalf("flushIdle() error = %v", err)
+	}
+	if got := readMessages(t, writer.GetFilePath())[flushTopic]; len(got) != 1 {
+		t.Errorf("flush messages = %v, want no flush without pending messages", got)
+	}
+}
+
+func TestCloseStopsFlusher(t *testing.T) {
+	writer := newTestWriter(t, options.FoxgloveOptions{FlushIntervalSeconds: 1})
+	if writer.stopFlush == nil {
+		t.Fatal("flusher not started")
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if writer.stopFlush != nil {
+		t.Error("Close() did not stop the flusher")
+	}
+	if err := writer.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
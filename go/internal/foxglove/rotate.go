@@ -0,0 +1,94 @@
+package foxglove
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/foxglove/mcap/go/mcap"
+)
+
+// rotatedTimeLayout is the timestamp added to the names of files opened by Rotate
+const rotatedTimeLayout = "20060102-150405"
+
+// Rotate closes the current MCAP file and continues recording in a new one next to it, named after
+// FoxgloveOptions.McapPath with the writer clock's time (e.g. logs/pulse-20240102-150405.mcap).
+// Schemas, channels and metadata records are written to the new file with the same IDs, so existing
+// channels keep working and every file can be opened on its own. The live stream is not interrupted.
+func (u *UnifiedMcapWriter) Rotate() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return fmt.Errorf("MCAP writer %s is closed", u.filePath)
+	}
+
+	path, file, err := u.createRotatedFile()
+	if err != nil {
+		return err
+	}
+
+	writer, chunking, err := newMcapFileWriter(file, u.profile, u.opts.Compression)
+	if err == nil {
+		err = writeRecords(writer, u.records)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("failed to rotate MCAP file: %w", err)
+	}
+
+	// Finish the current file; recording continues in the new one even if this fails
+	closeErr := u.writer.Close()
+	if err := u.file.Close(); closeErr == nil {
+		closeErr = err
+	}
+
+	u.writer, u.chunking, u.file, u.filePath = writer, chunking, file, path
+	u.lastFlush, u.pending = time.Now(), false
+	if closeErr != nil {
+		return fmt.Errorf("failed to close rotated MCAP file: %w", closeErr)
+	}
+	return nil
+}
+
+// createRotatedFile creates the next file of Rotate. A counter is appended if a file with the
+// timestamped name already exists (several rotations within a second).
+func (u *UnifiedMcapWriter) createRotatedFile() (string, *os.File, error) {
+	ext := filepath.Ext(u.opts.McapPath)
+	base := strings.TrimSuffix(u.opts.McapPath, ext) + "-" + u.clock.Now().UTC().Format(rotatedTimeLayout)
+
+	path := base + ext
+	for n := 2; ; n++ {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if err == nil {
+			return path, file, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", nil, fmt.Errorf("failed to create MCAP file: %w", err)
+		}
+		path = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+}
+
+// writeRecords writes the schemas, channels and metadata of the previous file to a new one
+func writeRecords(writer *mcap.Writer, records []any) error {
+	for _, record := range records {
+		var err error
+		switch record := record.(type) {
+		case *mcap.Schema:
+			err = writer.WriteSchema(record)
+		case *mcap.Channel:
+			err = writer.WriteChannel(record)
+		case *mcap.Metadata:
+			err = writer.WriteMetadata(record)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
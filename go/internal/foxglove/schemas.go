@@ -12,6 +12,12 @@ func NewSchemaRegistry() *SchemaRegistry {
 			"foxglove.Log":           foxgloveLogSchema,
 			"mahcanirobotics.metric": shokkiMetricSchema,
 			"foxglove.Plot":          foxglovePlotSchema,
+
+			"mahcanirobotics.span_interval": spanIntervalSchema,
+
+			schemaPoseInFrame:    foxglovePoseInFrameSchema,
+			schemaFrameTransform: foxgloveFrameTransformSchema,
+			schemaSceneUpdate:    foxgloveSceneUpdateSchema,
 		},
 	}
 }
@@ -84,7 +90,8 @@ const shokkiMetricSchema = `{
       "description": "Timestamp of the metric sample"
     },
     "name": {"type": "string", "description": "Metric name"},
-    "value": {"type": "number", "description": "Metric value (plotted on Y-axis)"}
+    "value": {"type": "number", "description": "Metric value (plotted on Y-axis)"},
+    "attributes": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Metric dimensions"}
   },
   "required": ["timestamp", "name", "value"]
 }`
@@ -113,3 +120,209 @@ const foxglovePlotSchema = `{
   },
   "required": ["timestamp", "x", "y"]
 }`
+
+// spanIntervalSchema defines the schema for finished spans rendered as timeline intervals.
+// Each message carries the span start/end so operations can be scanned on a timeline,
+// with a status color for quick identification of failures.
+const spanIntervalSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "mahcanirobotics.span_interval",
+  "description": "A finished trace span as a time interval",
+  "type": "object",
+  "properties": {
+    "start": {
+      "type": "object",
+      "title": "time",
+      "properties": {
+        "sec": {"type": "integer", "minimum": 0},
+        "nsec": {"type": "integer", "minimum": 0, "maximum": 999999999}
+      },
+      "required": ["sec", "nsec"],
+      "description": "Span start time"
+    },
+    "end": {
+      "type": "object",
+      "title": "time",
+      "properties": {
+        "sec": {"type": "integer", "minimum": 0},
+        "nsec": {"type": "integer", "minimum": 0, "maximum": 999999999}
+      },
+      "required": ["sec", "nsec"],
+      "description": "Span end time"
+    },
+    "duration_ns": {"type": "integer", "minimum": 0, "description": "Span duration in nanoseconds"},
+    "name": {"type": "string", "description": "Span name"},
+    "trace_id": {"type": "string", "description": "Trace ID"},
+    "span_id": {"type": "string", "description": "Span ID"},
+    "parent_id": {"type": "string", "description": "Parent span ID"},
+    "status": {"type": "string", "enum": ["unset", "ok", "error"], "description": "Span status"},
+    "color": {"type": "string", "description": "Hex color for the status"},
+    "service_name": {"type": "string", "description": "Service name"},
+    "attributes": {"type": "object", "description": "Span attributes"}
+  },
+  "required": ["start", "end", "duration_ns", "name", "trace_id", "span_id", "status", "color", "service_name"]
+}`
+
+// Building blocks of the official Foxglove spatial schemas
+// https://github.com/foxglove/schemas/tree/main/schemas/jsonschema
+const (
+	foxgloveTimeSchema = `{
+      "type": "object",
+      "title": "time",
+      "properties": {
+        "sec": {"type": "integer", "minimum": 0},
+        "nsec": {"type": "integer", "minimum": 0, "maximum": 999999999}
+      }
+    }`
+	foxgloveDurationSchema = `{
+      "type": "object",
+      "title": "duration",
+      "properties": {
+        "sec": {"type": "integer"},
+        "nsec": {"type": "integer", "minimum": 0, "maximum": 999999999}
+      }
+    }`
+	foxgloveVector3Schema = `{
+      "type": "object",
+      "title": "foxglove.Vector3",
+      "properties": {"x": {"type": "number"}, "y": {"type": "number"}, "z": {"type": "number"}}
+    }`
+	foxgloveQuaternionSchema = `{
+      "type": "object",
+      "title": "foxglove.Quaternion",
+      "properties": {"x": {"type": "number"}, "y": {"type": "number"}, "z": {"type": "number"}, "w": {"type": "number"}}
+    }`
+	foxglovePoseSchema = `{
+      "type": "object",
+      "title": "foxglove.Pose",
+      "properties": {"position": ` + foxgloveVector3Schema + `, "orientation": ` + foxgloveQuaternionSchema + `}
+    }`
+	foxgloveColorSchema = `{
+      "type": "object",
+      "title": "foxglove.Color",
+      "properties": {"r": {"type": "number"}, "g": {"type": "number"}, "b": {"type": "number"}, "a": {"type": "number"}}
+    }`
+)
+
+// foxglovePoseInFrameSchema defines the Foxglove PoseInFrame schema (a pose in a coordinate frame)
+// https://github.com/foxglove/schemas/blob/main/schemas/jsonschema/PoseInFrame.json
+const foxglovePoseInFrameSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "foxglove.PoseInFrame",
+  "description": "A timestamped pose for an object or reference frame in 3D space",
+  "type": "object",
+  "properties": {
+    "timestamp": ` + foxgloveTimeSchema + `,
+    "frame_id": {"type": "string", "description": "Frame of reference for pose position and orientation"},
+    "pose": ` + foxglovePoseSchema + `
+  }
+}`
+
+// foxgloveFrameTransformSchema defines the Foxglove FrameTransform schema (the transform between two frames)
+// https://github.com/foxglove/schemas/blob/main/schemas/jsonschema/FrameTransform.json
+const foxgloveFrameTransformSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "foxglove.FrameTransform",
+  "description": "A transform between two reference frames in 3D space",
+  "type": "object",
+  "properties": {
+    "timestamp": ` + foxgloveTimeSchema + `,
+    "parent_frame_id": {"type": "string", "description": "Name of the parent frame"},
+    "child_frame_id": {"type": "string", "description": "Name of the child frame"},
+    "translation": ` + foxgloveVector3Schema + `,
+    "rotation": ` + foxgloveQuaternionSchema + `
+  }
+}`
+
+// foxgloveSceneUpdateSchema defines the Foxglove SceneUpdate schema for markers in the 3D panel.
+// Only the arrow, cube, sphere and text primitives written by SceneEntity are described; the
+// other primitive lists are always empty.
+// https://github.com/foxglove/schemas/blob/main/schemas/jsonschema/SceneUpdate.json
+const foxgloveSceneUpdateSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "foxglove.SceneUpdate",
+  "description": "An update to the entities displayed in a 3D scene",
+  "type": "object",
+  "properties": {
+    "deletions": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "title": "foxglove.SceneEntityDeletion",
+        "properties": {
+          "timestamp": ` + foxgloveTimeSchema + `,
+          "type": {"type": "integer", "description": "0 = MATCHING_ID, 1 = ALL"},
+          "id": {"type": "string", "description": "Identifier of the entity to delete (MATCHING_ID only)"}
+        }
+      }
+    },
+    "entities": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "title": "foxglove.SceneEntity",
+        "properties": {
+          "timestamp": ` + foxgloveTimeSchema + `,
+          "frame_id": {"type": "string"},
+          "id": {"type": "string"},
+          "lifetime": ` + foxgloveDurationSchema + `,
+          "frame_locked": {"type": "boolean"},
+          "metadata": {
+            "type": "array",
+            "items": {"type": "object", "properties": {"key": {"type": "string"}, "value": {"type": "string"}}}
+          },
+          "arrows": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "title": "foxglove.ArrowPrimitive",
+              "properties": {
+                "pose": ` + foxglovePoseSchema + `,
+                "shaft_length": {"type": "number"},
+                "shaft_diameter": {"type": "number"},
+                "head_length": {"type": "number"},
+                "head_diameter": {"type": "number"},
+                "color": ` + foxgloveColorSchema + `
+              }
+            }
+          },
+          "cubes": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "title": "foxglove.CubePrimitive",
+              "properties": {"pose": ` + foxglovePoseSchema + `, "size": ` + foxgloveVector3Schema + `, "color": ` + foxgloveColorSchema + `}
+            }
+          },
+          "spheres": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "title": "foxglove.SpherePrimitive",
+              "properties": {"pose": ` + foxglovePoseSchema + `, "size": ` + foxgloveVector3Schema + `, "color": ` + foxgloveColorSchema + `}
+            }
+          },
+          "texts": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "title": "foxglove.TextPrimitive",
+              "properties": {
+                "pose": ` + foxglovePoseSchema + `,
+                "billboard": {"type": "boolean"},
+                "font_size": {"type": "number"},
+                "scale_invariant": {"type": "boolean"},
+                "color": ` + foxgloveColorSchema + `,
+                "text": {"type": "string"}
+              }
+            }
+          },
+          "cylinders": {"type": "array"},
+          "lines": {"type": "array"},
+          "triangles": {"type": "array"},
+          "models": {"type": "array"}
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,259 @@
+package foxglove
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Names of the official Foxglove schemas used for spatial data. They are registered to the
+// MCAP file the first time a message of the schema is written.
+const (
+	schemaPoseInFrame    = "foxglove.PoseInFrame"
+	schemaFrameTransform = "foxglove.FrameTransform"
+	schemaSceneUpdate    = "foxglove.SceneUpdate"
+)
+
+// Timestamp is a point in time in Foxglove format
+type Timestamp struct {
+	Sec  uint32 `json:"sec"`
+	Nsec uint32 `json:"nsec"`
+}
+
+// NewTimestamp converts a time to Foxglove format
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Sec: uint32(t.Unix()), Nsec: uint32(t.Nanosecond())}
+}
+
+// Time returns the timestamp as a time.Time
+func (ts Timestamp) Time() time.Time {
+	return time.Unix(int64(ts.Sec), int64(ts.Nsec))
+}
+
+// Duration is a time span in Foxglove format
+type Duration struct {
+	Sec  int32  `json:"sec"`
+	Nsec uint32 `json:"nsec"`
+}
+
+// NewDuration converts a duration to Foxglove format
+func NewDuration(d time.Duration) Duration {
+	return Duration{Sec: int32(d / time.Second), Nsec: uint32(d % time.Second)}
+}
+
+// Vector3 is a vector in 3D space (foxglove.Vector3)
+type Vector3 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// Quaternion is a rotation in 3D space (foxglove.Quaternion). The zero value is not a valid
+// rotation; use Quaternion{W: 1} for no rotation.
+type Quaternion struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+	W float64 `json:"w"`
+}
+
+// Pose is a position and orientation in 3D space (foxglove.Pose)
+type Pose struct {
+	Position    Vector3    `json:"position"`
+	Orientation Quaternion `json:"orientation"`
+}
+
+// Color is an RGBA color with components from 0 to 1 (foxglove.Color)
+type Color struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+	A float64 `json:"a"`
+}
+
+// PoseInFrame is a timestamped pose in a coordinate frame (foxglove.PoseInFrame)
+type PoseInFrame struct {
+	Timestamp Timestamp `json:"timestamp"` // Zero uses the writer's clock
+	FrameID   string    `json:"frame_id"`
+	Pose      Pose      `json:"pose"`
+}
+
+// FrameTransform is the transform from a parent frame to a child frame (foxglove.FrameTransform)
+type FrameTransform struct {
+	Timestamp     Timestamp  `json:"timestamp"` // Zero uses the writer's clock
+	ParentFrameID string     `json:"parent_frame_id"`
+	ChildFrameID  string     `json:"child_frame_id"`
+	Translation   Vector3    `json:"translation"`
+	Rotation      Quaternion `json:"rotation"`
+}
+
+// SceneUpdate adds, replaces or deletes entities of the 3D panel (foxglove.SceneUpdate)
+type SceneUpdate struct {
+	Deletions []SceneEntityDeletion `json:"deletions"`
+	Entities  []SceneEntity         `json:"entities"`
+}
+
+// SceneEntityDeletionType selects which entities a SceneEntityDeletion removes
+type SceneEntityDeletionType int
+
+const (
+	DeleteMatchingID SceneEntityDeletionType = 0 // Delete the entity with the given ID
+	DeleteAll        SceneEntityDeletionType = 1 // Delete every entity on the topic
+)
+
+// SceneEntityDeletion deletes entities of earlier scene updates (foxglove.SceneEntityDeletion)
+type SceneEntityDeletion struct {
+	Timestamp Timestamp               `json:"timestamp"` // Zero uses the writer's clock
+	Type      SceneEntityDeletionType `json:"type"`
+	ID        string                  `json:"id"` // Only for DeleteMatchingID
+}
+
+// SceneEntity is a set of primitives shown together in the 3D panel (foxglove.SceneEntity).
+// An entity replaces an earlier one with the same ID on the same topic.
+type SceneEntity struct {
+	Timestamp   Timestamp         `json:"timestamp"` // Zero uses the writer's clock
+	FrameID     string            `json:"frame_id"`
+	ID          string            `json:"id"`
+	Lifetime    Duration          `json:"lifetime"`     // Zero shows the entity until it is replaced or deleted
+	FrameLocked bool              `json:"frame_locked"` // Move the entity with its frame instead of keeping its position at Timestamp
+	Metadata    []KeyValuePair    `json:"metadata"`
+	Arrows      []ArrowPrimitive  `json:"arrows"`
+	Cubes       []CubePrimitive   `json:"cubes"`
+	Spheres     []SpherePrimitive `json:"spheres"`
+	Texts       []TextPrimitive   `json:"texts"`
+}
+
+// KeyValuePair is an entry of SceneEntity.Metadata
+type KeyValuePair struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ArrowPrimitive is an arrow pointing along the x axis of its pose (foxglove.ArrowPrimitive)
+type ArrowPrimitive struct {
+	Pose          Pose    `json:"pose"`
+	ShaftLength   float64 `json:"shaft_length"`
+	ShaftDiameter float64 `json:"shaft_diameter"`
+	HeadLength    float64 `json:"head_length"`
+	HeadDiameter  float64 `json:"head_diameter"`
+	Color         Color   `json:"color"`
+}
+
+// CubePrimitive is a box centered at its pose (foxglove.CubePrimitive)
+type CubePrimitive struct {
+	Pose  Pose    `json:"pose"`
+	Size  Vector3 `json:"size"`
+	Color Color   `json:"color"`
+}
+
+// SpherePrimitive is an ellipsoid centered at its pose (foxglove.SpherePrimitive)
+type SpherePrimitive struct {
+	Pose  Pose    `json:"pose"`
+	Size  Vector3 `json:"size"` // Diameters along each axis
+	Color Color   `json:"color"`
+}
+
+// TextPrimitive is a text label (foxglove.TextPrimitive)
+type TextPrimitive struct {
+	Pose           Pose    `json:"pose"`
+	Billboard      bool    `json:"billboard"`       // Always face the camera
+	FontSize       float64 `json:"font_size"`       // Meters, or pixels with ScaleInvariant
+	ScaleInvariant bool    `json:"scale_invariant"` // Keep the same size on screen when zooming
+	Color          Color   `json:"color"`
+	Text           string  `json:"text"`
+}
+
+// MarshalJSON writes every primitive list of the schema, as empty arrays where unset,
+// since the 3D panel expects all of them
+func (e SceneEntity) MarshalJSON() ([]byte, error) {
+	type entity SceneEntity // Without the MarshalJSON method
+	out := struct {
+		entity
+		Cylinders []struct{} `json:"cylinders"`
+		Lines     []struct{} `json:"lines"`
+		Triangles []struct{} `json:"triangles"`
+		Models    []struct{} `json:"models"`
+	}{entity: entity(e), Cylinders: []struct{}{}, Lines: []struct{}{}, Triangles: []struct{}{}, Models: []struct{}{}}
+
+	out.Metadata = emptyIfNil(out.Metadata)
+	out.Arrows = emptyIfNil(out.Arrows)
+	out.Cubes = emptyIfNil(out.Cubes)
+	out.Spheres = emptyIfNil(out.Spheres)
+	out.Texts = emptyIfNil(out.Texts)
+	return json.Marshal(out)
+}
+
+// emptyIfNil returns an empty slice for nil, so it is marshaled as [] instead of null
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// WritePoseInFrame writes a pose to topic using the foxglove.PoseInFrame schema
+func (u *UnifiedMcapWriter) WritePoseInFrame(topic string, msg PoseInFrame) error {
+	msg.Timestamp = u.stamp(msg.Timestamp)
+	return u.writeSpatial(topic, schemaPoseInFrame, msg.Timestamp, msg)
+}
+
+// WriteFrameTransform writes a transform to topic using the foxglove.FrameTransform schema
+// (e.g., "/tf", shown by the 3D panel for every frame)
+func (u *UnifiedMcapWriter) WriteFrameTransform(topic string, msg FrameTransform) error {
+	msg.Timestamp = u.stamp(msg.Timestamp)
+	return u.writeSpatial(topic, schemaFrameTransform, msg.Timestamp, msg)
+}
+
+// WriteSceneUpdate writes markers to topic using the foxglove.SceneUpdate schema.
+// Entities and deletions without a timestamp get the current time of the writer's clock.
+func (u *UnifiedMcapWriter) WriteSceneUpdate(topic string, msg SceneUpdate) error {
+	now := NewTimestamp(u.Now())
+
+	entities := make([]SceneEntity, len(msg.Entities))
+	for i, entity := range msg.Entities {
+		if entity.Timestamp == (Timestamp{}) {
+			entity.Timestamp = now
+		}
+		entities[i] = entity
+	}
+	deletions := make([]SceneEntityDeletion, len(msg.Deletions))
+	for i, deletion := range msg.Deletions {
+		if deletion.Timestamp == (Timestamp{}) {
+			deletion.Timestamp = now
+		}
+		deletions[i] = deletion
+	}
+
+	return u.writeSpatial(topic, schemaSceneUpdate, now, SceneUpdate{Deletions: deletions, Entities: entities})
+}
+
+// stamp returns ts, or the current time of the writer's clock if ts is zero
+func (u *UnifiedMcapWriter) stamp(ts Timestamp) Timestamp {
+	if ts == (Timestamp{}) {
+		return NewTimestamp(u.Now())
+	}
+	return ts
+}
+
+// writeSpatial registers the schema and the topic's channel on first use and writes msg as JSON,
+// logged at the message timestamp
+func (u *UnifiedMcapWriter) writeSpatial(topic, schemaName string, ts Timestamp, msg any) error {
+	if u.IsClosed() {
+		return fmt.Errorf("MCAP writer is closed")
+	}
+	if err := u.RegisterSchema(schemaName); err != nil {
+		return err
+	}
+	channelID, err := u.CreateChannel(topic, schemaName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s channel %s: %w", schemaName, topic, err)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %w", schemaName, err)
+	}
+
+	logTime := uint64(ts.Time().UnixNano())
+	return u.WriteMessage(channelID, data, logTime, logTime)
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/machanirobotics/pulse/go/internal/tags"
+	"go.opentelemetry.io/otel/log"
+)
+
+// Attr creates a per-call log attribute from any Go value,
+// e.g. logger.Info("Order placed", order, logging.Attr("retry", 2))
+func Attr(key string, value any) KeyValue {
+	return convertToOtelKeyValue(key, value)
+}
+
+// splitData separates the primary data (data[0]) from the per-call attributes that follow it.
+// Trailing arguments may be KeyValue values (see Attr), map[string]any, or structs (and pointers
+// to structs) whose `pulse:"attribute:key"` fields are merged in (untagged fields too if auto is set).
+// Any other trailing value is kept as a "data.{index}" attribute, e.g. data.2 for the third argument.
+// If data[0] is itself a KeyValue, there is no primary data and every argument is an attribute.
+// Attributes are returned in call order (map keys sorted), so later ones take precedence.
+func splitData(data []any, auto bool) (primary any, extras []KeyValue) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	start := 1
+	switch data[0].(type) {
+	case KeyValue, []KeyValue:
+		start = 0
+	default:
+		primary = data[0]
+	}
+
+	for i := start; i < len(data); i++ {
+		switch v := data[i].(type) {
+		case KeyValue:
+			extras = append(extras, v)
+		case []KeyValue:
+			extras = append(extras, v...)
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				extras = append(extras, convertToOtelKeyValue(k, v[k]))
+			}
+		case nil:
+		default:
+			rv := reflect.ValueOf(v)
+			if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+				rv = rv.Elem()
+			}
+			if rv.Kind() == reflect.Struct {
+				extras = append(extras, tagAttributes(rv, auto)...)
+			} else {
+				extras = append(extras, convertToOtelKeyValue(fmt.Sprintf("data.%d", i), v))
+			}
+		}
+	}
+
+	return primary, extras
+}
+
+// filterData applies the attribute allow/deny policy (LoggingOptions.Attributes) to the data of a record
+// before it reaches any output. Per-call attributes and With fields with a denied key are dropped. A struct
+// or map primary is replaced by a map of its allowed top-level fields, keyed as they are shown on the console
+// and in MCAP (JSON names for structs, see convertToMap); any other primary is kept only if "data" is allowed.
+// Without a policy, the data is returned unchanged.
+func filterData(filter *tags.Filter, primary any, extras []KeyValue) (any, []KeyValue) {
+	if filter == nil {
+		return primary, extras
+	}
+
+	allowed := make([]KeyValue, 0, len(extras))
+	for _, kv := range extras {
+		if filter.Allow(kv.Key) {
+			allowed = append(allowed, kv)
+		}
+	}
+
+	rv := reflect.ValueOf(primary)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map:
+		fields := convertToMap(primary)
+		for key := range fields {
+			if !filter.Allow(key) {
+				delete(fields, key)
+			}
+		}
+		return fields, allowed
+	default:
+		if primary != nil && !filter.Allow("data") {
+			primary = nil
+		}
+		return primary, allowed
+	}
+}
+
+// dataMap converts the primary data to a map (see convertToMap) and merges the per-call
+// attributes into it, overriding fields with the same key
+func dataMap(primary any, extras []KeyValue) map[string]interface{} {
+	result := convertToMap(primary)
+	if len(extras) == 0 {
+		return result
+	}
+
+	if result == nil {
+		result = make(map[string]interface{}, len(extras))
+	}
+	for _, kv := range extras {
+		result[kv.Key] = valueToInterface(kv.Value)
+	}
+	return result
+}
+
+// valueToInterface converts an OpenTelemetry log value to a Go value for console and MCAP output
+func valueToInterface(v Value) interface{} {
+	switch v.Kind() {
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindString:
+		return v.AsString()
+	case log.KindBytes:
+		return v.AsBytes()
+	case log.KindSlice:
+		values := v.AsSlice()
+		result := make([]interface{}, 0, len(values))
+		for _, item := range values {
+			result = append(result, valueToInterface(item))
+		}
+		return result
+	case log.KindMap:
+		result := make(map[string]interface{})
+		for _, kv := range v.AsMap() {
+			result[kv.Key] = valueToInterface(kv.Value)
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+type order struct {
+	ID     string `json:"id" pulse:"attribute:order.id"`
+	Status string `json:"status"`
+}
+
+type retryInfo struct {
+	Attempt int `pulse:"attribute:attempt"`
+}
+
+// newTestLogger returns a Logger that keeps its records in RecentLogs
+func newTestLogger() *Logger {
+	opts := options.LoggingOptions{}
+	opts.Log.RingBufferSize = 8
+	return NewLogger(options.ServiceOptions{Name: "test"}, opts, nil, nil, nil)
+}
+
+func TestSplitData(t *testing.T) {
+	primary, extras := splitData([]any{
+		order{ID: "42"},
+		Attr("retry", true),
+		map[string]any{"b": 2, "a": 1},
+		retryInfo{Attempt: 3},
+		"loose",
+	}, false)
+
+	if _, ok := primary.(order); !ok {
+		t.Fatalf("primary = %T, want order", primary)
+	}
+
+	var keys []string
+	for _, kv := range extras {
+		keys = append(keys, kv.Key)
+	}
+	want := []string{"retry", "a", "b", "attempt", "data.4"}
+	if len(keys) != len(want) {
+		t.Fatalf("extras = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("extras[%d] = %s, want %s", i, keys[i], want[i])
+		}
+	}
+}
+
+func TestSplitDataWithoutPrimary(t *testing.T) {
+	primary, extras := splitData([]any{Attr("a", 1), Attr("b", 2)}, false)
+	if primary != nil {
+		t.Errorf("primary = %v, want nil", primary)
+	}
+	if len(extras) != 2 {
+		t.Errorf("got %d extras, want 2", len(extras))
+	}
+}
+
+func TestDataPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []any
+		with  []KeyValue
+		key   string
+		value any
+	}{
+		{
+			name:  "per-call attribute overrides primary field",
+			data:  []any{order{ID: "42", Status: "new"}, Attr("status", "paid")},
+			key:   "status",
+			value: "paid",
+		},
+		{
+			name:  "later attribute overrides earlier one",
+			data:  []any{order{ID: "42"}, Attr("status", "paid"), map[string]any{"status": "shipped"}},
+			key:   "status",
+			value: "shipped",
+		},
+		{
+			name:  "per-call attribute overrides With field",
+			data:  []any{order{ID: "42"}, Attr("status", "paid")},
+			with:  []KeyValue{Attr("status", "pending")},
+			key:   "status",
+			value: "paid",
+		},
+		{
+			name:  "With field overrides primary field",
+			data:  []any{order{ID: "42", Status: "new"}},
+			with:  []KeyValue{Attr("status", "pending")},
+			key:   "status",
+			value: "pending",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := newTestLogger()
+			if len(tt.with) > 0 {
+				logger = logger.With(tt.with...)
+			}
+			logger.Info("Order updated", tt.data...)
+
+			logs := logger.RecentLogs()
+			if len(logs) != 1 {
+				t.Fatalf("got %d records, want 1", len(logs))
+			}
+			if got := logs[0].Data[tt.key]; got != tt.value {
+				t.Errorf("%s = %v, want %v", tt.key, got, tt.value)
+			}
+			if got := logs[0].Data["id"]; got != "42" {
+				t.Errorf("id = %v, want 42", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+)
+
+// ContextLogger adapts Logger to context-first logging interfaces such as
+//
+//	type Logger interface {
+//	    Info(ctx context.Context, msg string, keyvals ...any)
+//	    ...
+//	}
+//
+// Every call is correlated with the span in ctx. keyvals are alternating keys and values
+// (e.g., "order_id", 42); KeyValue arguments (see Attr) may be mixed in. A key without a
+// value is logged as "!BADKEY" so the value is not lost.
+type ContextLogger struct {
+	logger *Logger
+}
+
+// ContextLogger returns a context-first adapter backed by this logger
+func (l *Logger) ContextLogger() *ContextLogger {
+	return &ContextLogger{logger: l}
+}
+
+// Debug logs a debug-level message with key-value attributes
+func (c *ContextLogger) Debug(ctx context.Context, msg string, keyvals ...any) {
+	c.logger.WithContext(ctx).log(log.DebugLevel, msg, keyValueAttrs(keyvals)...)
+}
+
+// Info logs an info-level message with key-value attributes
+func (c *ContextLogger) Info(ctx context.Context, msg string, keyvals ...any) {
+	c.logger.WithContext(ctx).log(log.InfoLevel, msg, keyValueAttrs(keyvals)...)
+}
+
+// Warn logs a warning-level message with key-value attributes
+func (c *ContextLogger) Warn(ctx context.Context, msg string, keyvals ...any) {
+	c.logger.WithContext(ctx).log(log.WarnLevel, msg, keyValueAttrs(keyvals)...)
+}
+
+// Error logs an error-level message with key-value attributes
+func (c *ContextLogger) Error(ctx context.Context, msg string, keyvals ...any) {
+	c.logger.WithContext(ctx).log(log.ErrorLevel, msg, keyValueAttrs(keyvals)...)
+}
+
+// keyValueAttrs converts alternating keys and values to per-call attributes.
+// Non-string keys are formatted with fmt; a trailing key without a value becomes "!BADKEY".
+func keyValueAttrs(keyvals []any) []any {
+	attrs := make([]any, 0, len(keyvals)/2+1)
+	for i := 0; i < len(keyvals); i++ {
+		if kv, ok := keyvals[i].(KeyValue); ok {
+			attrs = append(attrs, kv)
+			continue
+		}
+		if i == len(keyvals)-1 {
+			attrs = append(attrs, Attr("!BADKEY", keyvals[i]))
+			break
+		}
+
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		attrs = append(attrs, Attr(key, keyvals[i+1]))
+		i++
+	}
+	return attrs
+}
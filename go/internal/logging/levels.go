@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// Level is a log level. Besides the built-in levels, custom levels can be
+// registered with Logger.RegisterLevel and used with Logger.Log.
+type Level = log.Level
+
+// Built-in log levels
+const (
+	TraceLevel  Level = -8             // Very verbose tracing, below Debug
+	DebugLevel  Level = log.DebugLevel // Debug level
+	InfoLevel   Level = log.InfoLevel  // Info level
+	NoticeLevel Level = 2              // Normal but significant events, between Info and Warn
+	WarnLevel   Level = log.WarnLevel  // Warning level
+	ErrorLevel  Level = log.ErrorLevel // Error level
+	FatalLevel  Level = log.FatalLevel // Fatal level
+)
+
+// levelRegistry holds the names of custom log levels
+type levelRegistry struct {
+	mu    sync.RWMutex
+	names map[Level]string
+}
+
+// newLevelRegistry creates a registry with the built-in custom levels (trace, notice)
+func newLevelRegistry() *levelRegistry {
+	return &levelRegistry{
+		names: map[Level]string{
+			TraceLevel:  "trace",
+			NoticeLevel: "notice",
+		},
+	}
+}
+
+// register adds or renames a custom level
+func (r *levelRegistry) register(level Level, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names[level] = strings.ToLower(name)
+}
+
+// name returns the name of a level, falling back to the nearest standard level name
+func (r *levelRegistry) name(level Level) string {
+	if name := level.String(); name != "" {
+		return name
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name, ok := r.names[level]; ok {
+		return name
+	}
+	return nearestLevel(level).String()
+}
+
+// styles returns the console styles including the registered custom levels
+func (r *levelRegistry) styles() *log.Styles {
+	styles := log.DefaultStyles()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for level, name := range r.names {
+		styles.Levels[level] = lipgloss.NewStyle().
+			SetString(strings.ToUpper(name)).
+			Bold(true).
+			MaxWidth(4)
+	}
+	return styles
+}
+
+// nearestLevel returns the standard level whose band contains the given level.
+// Levels below Debug map to Debug and levels between two standard levels map to the lower one.
+func nearestLevel(level Level) Level {
+	switch {
+	case level < log.InfoLevel:
+		return log.DebugLevel
+	case level < log.WarnLevel:
+		return log.InfoLevel
+	case level < log.ErrorLevel:
+		return log.WarnLevel
+	case level < log.FatalLevel:
+		return log.ErrorLevel
+	default:
+		return log.FatalLevel
+	}
+}
+
+// toOtelSeverity maps a log level to the nearest OpenTelemetry severity.
+// Standard levels map to the first severity of their band (e.g., Info -> INFO),
+// custom levels are placed within the band by their distance to the standard level
+// (e.g., Notice -> INFO3) and levels below Debug map to the TRACE band.
+func toOtelSeverity(level Level) otellog.Severity {
+	var base otellog.Severity
+	var start Level
+
+	switch {
+	case level < log.DebugLevel:
+		base, start = otellog.SeverityTrace1, TraceLevel
+	case level < log.InfoLevel:
+		base, start = otellog.SeverityDebug1, log.DebugLevel
+	case level < log.WarnLevel:
+		base, start = otellog.SeverityInfo1, log.InfoLevel
+	case level < log.ErrorLevel:
+		base, start = otellog.SeverityWarn1, log.WarnLevel
+	case level < log.FatalLevel:
+		base, start = otellog.SeverityError1, log.ErrorLevel
+	default:
+		base, start = otellog.SeverityFatal1, log.FatalLevel
+	}
+
+	// Each band has four severities
+	offset := int(level - start)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > 3 {
+		offset = 3
+	}
+	return base + otellog.Severity(offset)
+}
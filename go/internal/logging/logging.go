@@ -4,13 +4,21 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/internal/tags"
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
 	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/baggage"
 	otellog "go.opentelemetry.io/otel/log"
 )
 
+// fatalFlushTimeout bounds how long Fatal/Fatalf wait for telemetry to flush before exiting
+const fatalFlushTimeout = 2 * time.Second
+
 // Logger is the main logging client for the pulse framework.
 // It wraps Charmbracelet's log.Logger and provides structured logging,
 // format-based logging, and hooks for MCAP/OTEL integration.
@@ -20,6 +28,19 @@ type Logger struct {
 	loggerService      *log.Logger
 	otelLogger         *OtelLogger
 	mcapWriter         *LogMcapWriter
+	recent             *logRingBuffer
+	filter             *tags.Filter
+	defaults           []otellog.KeyValue // From LoggingOptions.DefaultAttributes, added to every OTLP record
+	flushHook          func(context.Context) error
+	fatalExitCode      int    // Exit code used by Fatal/Fatalf
+	fatalPanic         bool   // Panic instead of exiting on Fatal/Fatalf
+	autoAttributes     bool   // Extract struct fields without a pulse tag (LogOptions.AutoAttributes)
+	flattenData        bool   // Print data fields as separate console key/value pairs (LogOptions.FlattenData)
+	sanitizeKeys       bool   // Rewrite OTLP attribute keys as Loki label names (LoggingOptions.SanitizeAttributeKeys)
+	hostName           string // Host name added to every record (LogOptions.IncludeHost), empty if disabled
+	levels             *levelRegistry
+	volume             *logVolume // Counts records per level in pulse.logs.emitted (nil without metrics)
+	fields             []KeyValue // Attributes added with With, before the per-call attributes of every record
 	ctx                context.Context
 	serviceName        string
 	serviceVersion     string
@@ -30,24 +51,51 @@ type Logger struct {
 // the provided service and logging options.
 // If otelLogger is provided, logs will be forwarded to OTLP/Loki.
 // If unifiedWriter is provided, logs will be written to MCAP files.
-func NewLogger(serviceOpts options.ServiceOptions, opts options.LoggingOptions, unifiedWriter *foxglove.UnifiedMcapWriter, otelLogger otellog.Logger) *Logger {
+// If meter is provided, emitted records are counted per level in pulse.logs.emitted.
+func NewLogger(serviceOpts options.ServiceOptions, opts options.LoggingOptions, unifiedWriter *foxglove.UnifiedMcapWriter, otelLogger otellog.Logger, meter *telemetry.Metrics) *Logger {
 	loggerService := log.NewWithOptions(os.Stderr, log.Options{
-		Prefix:          formatPrefix(serviceOpts),
+		Prefix:          resolvePrefix(serviceOpts, opts),
 		Level:           resolveLogLevel(serviceOpts.Environment),
-		ReportCaller:    true, // Always show file:line
-		ReportTimestamp: true, // Always show timestamp
+		ReportCaller:    !opts.Log.DisableCaller,    // Show file:line unless disabled
+		ReportTimestamp: !opts.Log.DisableTimestamp, // Show timestamp unless disabled
 		TimeFormat:      resolveTimeFormat(opts),
 		CallerOffset:    resolveCallerOffset(opts),
 	})
 
 	logger := &Logger{
 		loggerService:      loggerService,
+		filter:             tags.NewFilter(opts.Attributes),
+		defaults:           defaultAttributes(opts.DefaultAttributes),
+		fatalExitCode:      resolveFatalExitCode(opts),
+		fatalPanic:         opts.Log.FatalPanic,
+		autoAttributes:     opts.Log.AutoAttributes,
+		flattenData:        opts.Log.FlattenData,
+		sanitizeKeys:       opts.SanitizeAttributeKeys,
+		levels:             newLevelRegistry(),
+		volume:             newLogVolume(meter),
 		ctx:                context.Background(),
 		serviceName:        serviceOpts.Name,
 		serviceVersion:     serviceOpts.Version,
 		serviceEnvironment: string(serviceOpts.Environment),
 	}
 
+	// Show registered custom levels (trace, notice) on the console
+	loggerService.SetStyles(logger.levels.styles())
+
+	// Tell hosts (pods) apart when logs of several instances are aggregated
+	if opts.Log.IncludeHost {
+		logger.hostName = telemetry.HostName()
+		if logger.hostName != "" {
+			loggerService = loggerService.With("host", logger.hostName)
+			logger.loggerService = loggerService
+		}
+	}
+
+	// If a ring buffer size is configured, keep recent logs in memory
+	if opts.Log.RingBufferSize > 0 {
+		logger.recent = newLogRingBuffer(opts.Log.RingBufferSize)
+	}
+
 	// If OTLP logger is provided, set it up for forwarding
 	if otelLogger != nil {
 		logger.otelLogger = NewOtelLogger(otelLogger)
@@ -73,6 +121,19 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 		loggerService:      l.loggerService,
 		otelLogger:         l.otelLogger,
 		mcapWriter:         l.mcapWriter,
+		recent:             l.recent,
+		filter:             l.filter,
+		defaults:           l.defaults,
+		flushHook:          l.flushHook,
+		fatalExitCode:      l.fatalExitCode,
+		fatalPanic:         l.fatalPanic,
+		autoAttributes:     l.autoAttributes,
+		flattenData:        l.flattenData,
+		sanitizeKeys:       l.sanitizeKeys,
+		hostName:           l.hostName,
+		levels:             l.levels,
+		volume:             l.volume,
+		fields:             l.fields,
 		ctx:                ctx,
 		serviceName:        l.serviceName,
 		serviceVersion:     l.serviceVersion,
@@ -80,6 +141,37 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 	}
 }
 
+// With returns a Logger that adds attrs to every structured record (Info, Debug, ..., not the f variants),
+// after the attributes of earlier With calls, so l.With(a).With(b) logs both a and b.
+// Per-call attributes with the same key take precedence. l is not modified.
+func (l *Logger) With(attrs ...KeyValue) *Logger {
+	child := l.WithContext(l.ctx)
+
+	// Always copy, so appending to one child never writes into another's fields
+	child.fields = make([]KeyValue, 0, len(l.fields)+len(attrs))
+	child.fields = append(child.fields, l.fields...)
+	child.fields = append(child.fields, attrs...)
+	return child
+}
+
+// Log logs a message at the given level with optional structured data.
+// Use it with TraceLevel, NoticeLevel or levels registered with RegisterLevel.
+func (l *Logger) Log(level Level, msg string, data ...any) {
+	l.log(level, msg, data...)
+}
+
+// RegisterLevel registers a custom level name (e.g., "audit"). Custom levels are
+// mapped to the nearest OTLP severity band and Foxglove level.
+func (l *Logger) RegisterLevel(level Level, name string) {
+	l.levels.register(level, name)
+	l.loggerService.SetStyles(l.levels.styles())
+}
+
+// SetLevel sets the minimum level printed to the console (e.g., TraceLevel to show trace logs)
+func (l *Logger) SetLevel(level Level) {
+	l.loggerService.SetLevel(level)
+}
+
 // Info logs an info-level message with optional structured data.
 func (l *Logger) Info(msg string, data ...any) {
 	l.log(log.InfoLevel, msg, data...)
@@ -101,15 +193,60 @@ func (l *Logger) Error(msg string, data ...any) error {
 	return fmt.Errorf("%s", msg)
 }
 
-// Fatal logs a fatal-level message with optional structured data and exits the program.
+// Fatal logs a fatal-level message with optional structured data, flushes pending
+// telemetry and exits the program.
 func (l *Logger) Fatal(msg string, data ...any) {
 	l.log(log.FatalLevel, msg, data...)
-	os.Exit(1)
+	l.exit(msg)
+}
+
+// Enabled reports whether messages at level pass the logger level (see SetLevel)
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.loggerService.GetLevel()
+}
+
+// LogLazy logs a message at the given level, calling fn to build the structured data
+// only if the level is enabled. Disabled messages are dropped from every output
+// (console, OTLP and MCAP), so expensive data is never built for them.
+func (l *Logger) LogLazy(level Level, msg string, fn func() any) {
+	if l.Enabled(level) {
+		l.log(level, msg, fn())
+	}
+}
+
+// DebugLazy logs a debug-level message, building the data with fn only if debug is enabled.
+func (l *Logger) DebugLazy(msg string, fn func() any) {
+	if l.Enabled(log.DebugLevel) {
+		l.log(log.DebugLevel, msg, fn())
+	}
+}
+
+// InfoLazy logs an info-level message, building the data with fn only if info is enabled.
+func (l *Logger) InfoLazy(msg string, fn func() any) {
+	if l.Enabled(log.InfoLevel) {
+		l.log(log.InfoLevel, msg, fn())
+	}
+}
+
+// WarnLazy logs a warning-level message, building the data with fn only if warn is enabled.
+func (l *Logger) WarnLazy(msg string, fn func() any) {
+	if l.Enabled(log.WarnLevel) {
+		l.log(log.WarnLevel, msg, fn())
+	}
+}
+
+// ErrorLazy logs an error-level message, building the data with fn only if error is enabled.
+func (l *Logger) ErrorLazy(msg string, fn func() any) error {
+	if l.Enabled(log.ErrorLevel) {
+		l.log(log.ErrorLevel, msg, fn())
+	}
+	return fmt.Errorf("%s", msg)
 }
 
 // Infof logs an info-level message using a format string.
 func (l *Logger) Infof(format string, args ...any) {
 	l.loggerService.Infof(format, args...)
+	l.recordRecent(log.InfoLevel, fmt.Sprintf(format, args...), nil, 2)
 	if l.otelLogger != nil {
 		l.otelLogger.WithContext(l.ctx).Infof(format, args...)
 	}
@@ -118,6 +255,7 @@ func (l *Logger) Infof(format string, args ...any) {
 // Debugf logs a debug-level message using a format string.
 func (l *Logger) Debugf(format string, args ...any) {
 	l.loggerService.Debugf(format, args...)
+	l.recordRecent(log.DebugLevel, fmt.Sprintf(format, args...), nil, 2)
 	if l.otelLogger != nil {
 		l.otelLogger.WithContext(l.ctx).Debugf(format, args...)
 	}
@@ -126,6 +264,7 @@ func (l *Logger) Debugf(format string, args ...any) {
 // Warnf logs a warning-level message using a format string.
 func (l *Logger) Warnf(format string, args ...any) {
 	l.loggerService.Warnf(format, args...)
+	l.recordRecent(log.WarnLevel, fmt.Sprintf(format, args...), nil, 2)
 	if l.otelLogger != nil {
 		l.otelLogger.WithContext(l.ctx).Warnf(format, args...)
 	}
@@ -134,28 +273,64 @@ func (l *Logger) Warnf(format string, args ...any) {
 // Errorf logs an error-level message using a format string.
 func (l *Logger) Errorf(format string, args ...interface{}) error {
 	l.loggerService.Errorf(format, args...)
+	l.recordRecent(log.ErrorLevel, fmt.Sprintf(format, args...), nil, 2)
 	if l.otelLogger != nil {
 		l.otelLogger.WithContext(l.ctx).Errorf(format, args...)
 	}
 	return fmt.Errorf(format, args...)
 }
 
-// Fatalf logs a fatal-level message using a format string and exits the program.
+// Fatalf logs a fatal-level message using a format string, flushes pending telemetry
+// and exits the program.
 func (l *Logger) Fatalf(format string, args ...any) {
-	l.loggerService.Fatalf(format, args...)
+	l.loggerService.Logf(log.FatalLevel, format, args...) // Fatalf would exit before flushing
+	l.recordRecent(log.FatalLevel, fmt.Sprintf(format, args...), nil, 2)
 	if l.otelLogger != nil {
 		l.otelLogger.WithContext(l.ctx).Fatalf(format, args...)
 	}
-	os.Exit(1)
+	l.exit(fmt.Sprintf(format, args...))
+}
+
+// exit flushes pending telemetry and ends the program after a fatal log with the configured
+// exit code. If LogOptions.FatalPanic is set, it panics with the message instead (without
+// flushing, since the program keeps running if the panic is recovered).
+func (l *Logger) exit(msg string) {
+	if l.fatalPanic {
+		panic(fmt.Errorf("fatal: %s", msg))
+	}
+	l.flushBeforeExit()
+	os.Exit(l.fatalExitCode)
 }
 
 // log is the internal handler for all log levels, with optional structured data.
+// data[0] is the primary data (struct, map or value); any following arguments (KeyValue, maps or
+// structs, see splitData) are per-call attributes that take precedence over fields of the primary data.
 func (l *Logger) log(level log.Level, msg string, data ...any) {
+	primary, extras := splitData(data, l.autoAttributes)
+	if len(l.fields) > 0 {
+		extras = append(append(make([]KeyValue, 0, len(l.fields)+len(extras)), l.fields...), extras...)
+	}
+
+	// Struct tag attributes keep their own keys on OTLP, so they are taken before the data is filtered
+	var tagged []otellog.KeyValue
+	if l.filter != nil && l.otelLogger != nil {
+		tagged = structAttributes(primary, l.autoAttributes)
+	}
+	primary, extras = filterData(l.filter, primary, extras)
+	l.volume.add(l.ctx, l.levels.name(level))
+
 	// Log to stdout via charmbracelet logger
-	if len(data) == 0 {
+	if primary == nil && len(extras) == 0 {
 		l.loggerService.Log(level, msg)
 	} else {
-		sub := l.loggerService.With("data", formattedData(data[0]))
+		var keyvals []interface{}
+		if primary != nil {
+			keyvals = append(keyvals, l.consoleData(primary)...)
+		}
+		for _, kv := range extras {
+			keyvals = append(keyvals, kv.Key, valueToInterface(kv.Value))
+		}
+		sub := l.loggerService.With(keyvals...)
 		sub.Log(level, msg)
 	}
 
@@ -174,49 +349,106 @@ func (l *Logger) log(level log.Level, msg string, data ...any) {
 			otellog.String("code.filepath", file),
 			otellog.Int("code.lineno", line),
 		}
+		if l.hostName != "" {
+			attrs = append(attrs, otellog.String("host.name", l.hostName))
+		}
+
+		// Default attributes come first so baggage and log data with the same key override them
+		userAttrs := append([]otellog.KeyValue(nil), l.defaults...)
+
+		// Add baggage members propagated with the context (e.g., tenant, request_id)
+		for _, member := range baggage.FromContext(l.ctx).Members() {
+			userAttrs = append(userAttrs, otellog.String(member.Key(), member.Value()))
+		}
 
-		// Convert user data to OTLP attributes if present
-		if len(data) > 0 {
-			attrs = append(attrs, dataToOtelAttributes(data[0])...)
+		// Convert user data to OTLP attributes if present, then the per-call attributes
+		userAttrs = append(userAttrs, tagged...)
+		userAttrs = append(userAttrs, dataToOtelAttributes(primary, l.autoAttributes)...)
+		for _, kv := range extras {
+			userAttrs = append(userAttrs, kv)
 		}
 
-		// Map charmbracelet log levels to OTLP
-		switch level {
-		case log.DebugLevel:
-			otelLogger.Debug(msg, attrs...)
-		case log.InfoLevel:
-			otelLogger.Info(msg, attrs...)
-		case log.WarnLevel:
-			otelLogger.Warn(msg, attrs...)
-		case log.ErrorLevel:
-			otelLogger.Error(msg, attrs...)
-		case log.FatalLevel:
-			otelLogger.Fatal(msg, attrs...)
-		default:
-			otelLogger.Info(msg, attrs...)
+		// Apply the attribute allow/deny policy to user-provided attributes (by their original keys)
+		builtIn := len(attrs)
+		for _, attr := range userAttrs {
+			if l.filter.Allow(attr.Key) {
+				attrs = append(attrs, attr)
+			}
+		}
+		if l.sanitizeKeys {
+			attrs = sanitizeAttributes(attrs, builtIn)
 		}
+
+		// Map charmbracelet log levels to OTLP, with the level name as severity text
+		otelLogger.Log(toOtelSeverity(level), strings.ToUpper(l.levels.name(level)), msg, attrs...)
+	}
+
+	// Keep in the in-memory ring buffer if enabled
+	if l.recent != nil {
+		l.recordRecent(level, msg, dataMap(primary, extras), 3)
 	}
 
 	// Write to MCAP file if available
 	if l.mcapWriter != nil && !l.mcapWriter.IsClosed() {
-		levelStr := level.String()
+		levelStr := nearestLevel(level).String() // Foxglove only knows the standard levels
 
 		// Get caller information for file and line
 		file, line := getCallerInfo(3) // Skip 3 frames: getCallerInfo, log, and the calling function
 
-		// Convert structured data to map for MCAP
-		var dataMap map[string]interface{}
-		if len(data) > 0 {
-			dataMap = convertToMap(data[0])
-		}
-
-		// Write to MCAP with structured data in separate field
-		if err := l.mcapWriter.WriteLog(levelStr, msg, file, uint32(line), dataMap); err != nil {
+		// Write to MCAP with structured data (including per-call attributes) in separate field
+		if err := l.mcapWriter.WriteLog(levelStr, msg, file, uint32(line), dataMap(primary, extras)); err != nil {
 			l.loggerService.Warnf("Failed to write to MCAP: %v", err)
 		}
 	}
 }
 
+// recordRecent stores a log entry in the ring buffer if enabled.
+// skip is the number of stack frames between the caller of recordRecent and the user code.
+func (l *Logger) recordRecent(level log.Level, msg string, data map[string]interface{}, skip int) {
+	if l.recent == nil {
+		return
+	}
+
+	file, line := getCallerInfo(skip + 1)
+	l.recent.add(LogEntry{
+		Timestamp: time.Now(),
+		Level:     l.levels.name(level),
+		Message:   msg,
+		File:      file,
+		Line:      line,
+		Data:      data,
+	})
+}
+
+// RecentLogs returns the most recent log entries, oldest first.
+// Returns nil if the ring buffer is disabled (LogOptions.RingBufferSize is 0).
+func (l *Logger) RecentLogs() []LogEntry {
+	if l.recent == nil {
+		return nil
+	}
+	return l.recent.snapshot()
+}
+
+// SetFlushHook sets the function called by Fatal/Fatalf to flush pending telemetry
+// (OTLP batch processors, MCAP writer) before the program exits
+func (l *Logger) SetFlushHook(fn func(context.Context) error) {
+	l.flushHook = fn
+}
+
+// flushBeforeExit runs the flush hook with a short timeout so fatal logs are not lost
+func (l *Logger) flushBeforeExit() {
+	if l.flushHook == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	defer cancel()
+
+	if err := l.flushHook(ctx); err != nil {
+		l.loggerService.Warnf("Failed to flush telemetry before exit: %v", err)
+	}
+}
+
 // Close closes the logger and any associated resources (e.g., MCAP writer)
 func (l *Logger) Close() error {
 	if l.mcapWriter != nil && !l.mcapWriter.IsClosed() {
@@ -228,6 +460,13 @@ func (l *Logger) Close() error {
 	return nil
 }
 
+// Underlying returns the wrapped charmbracelet logger for styling or sub-loggers (log.With).
+// Logs written directly to it only reach the console: they are not forwarded to OTLP,
+// written to MCAP or kept in the recent logs buffer.
+func (l *Logger) Underlying() *log.Logger {
+	return l.loggerService
+}
+
 // GetMcapWriter returns the MCAP writer if available (useful for custom logging)
 func (l *Logger) GetMcapWriter() *LogMcapWriter {
 	return l.mcapWriter
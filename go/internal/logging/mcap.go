@@ -3,7 +3,6 @@ package logging
 import (
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
 	"github.com/machanirobotics/pulse/go/options"
@@ -82,7 +81,7 @@ func NewLogMcapWriter(serviceOpts options.ServiceOptions, unifiedWriter *foxglov
 
 // WriteLog writes a log message using the Foxglove Log schema
 func (l *LogMcapWriter) WriteLog(level, message, file string, line uint32, data map[string]interface{}) error {
-	now := time.Now()
+	now := l.unifiedWriter.Now()
 
 	// Convert string level to Foxglove level integer
 	levelInt := stringToFoxgloveLevel(level)
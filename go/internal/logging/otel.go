@@ -56,54 +56,40 @@ func (l *OtelLogger) WithContext(ctx context.Context) *OtelLogger {
 	}
 }
 
-// Info logs an informational message
-func (l *OtelLogger) Info(msg string, attrs ...KeyValue) *OtelLogger {
+// Log emits a log record with the given severity number and severity text (e.g., "INFO")
+func (l *OtelLogger) Log(severity log.Severity, severityText string, msg string, attrs ...KeyValue) *OtelLogger {
 	var record log.Record
 	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(log.SeverityInfo)
+	record.SetSeverity(severity)
+	record.SetSeverityText(severityText)
 	record.AddAttributes(attrs...)
 	l.logger.Emit(l.ctx, record)
 	return l
 }
 
+// Info logs an informational message
+func (l *OtelLogger) Info(msg string, attrs ...KeyValue) *OtelLogger {
+	return l.Log(log.SeverityInfo, log.SeverityInfo.String(), msg, attrs...)
+}
+
 // Debug logs a debug message
 func (l *OtelLogger) Debug(msg string, attrs ...KeyValue) *OtelLogger {
-	var record log.Record
-	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(log.SeverityDebug)
-	record.AddAttributes(attrs...)
-	l.logger.Emit(l.ctx, record)
-	return l
+	return l.Log(log.SeverityDebug, log.SeverityDebug.String(), msg, attrs...)
 }
 
 // Warn logs a warning message
 func (l *OtelLogger) Warn(msg string, attrs ...KeyValue) *OtelLogger {
-	var record log.Record
-	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(log.SeverityWarn)
-	record.AddAttributes(attrs...)
-	l.logger.Emit(l.ctx, record)
-	return l
+	return l.Log(log.SeverityWarn, log.SeverityWarn.String(), msg, attrs...)
 }
 
 // Error logs an error message
 func (l *OtelLogger) Error(msg string, attrs ...KeyValue) *OtelLogger {
-	var record log.Record
-	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(log.SeverityError)
-	record.AddAttributes(attrs...)
-	l.logger.Emit(l.ctx, record)
-	return l
+	return l.Log(log.SeverityError, log.SeverityError.String(), msg, attrs...)
 }
 
 // Fatal logs a fatal message
 func (l *OtelLogger) Fatal(msg string, attrs ...KeyValue) *OtelLogger {
-	var record log.Record
-	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(log.SeverityFatal)
-	record.AddAttributes(attrs...)
-	l.logger.Emit(l.ctx, record)
-	return l
+	return l.Log(log.SeverityFatal, log.SeverityFatal.String(), msg, attrs...)
 }
 
 // Infof logs an informational message with formatting
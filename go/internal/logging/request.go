@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Request logs a handled request in one line with consistent keys: request.method,
+// response.status, duration_ms, error (if any), and the `pulse:"attribute:key"` fields of
+// req and resp as request.{key} and response.{key}.
+// The method is "METHOD /path" for *http.Request and the struct type name otherwise; the
+// status is the status code for *http.Response and "ok" or "error" otherwise.
+// Failures (a non-nil err or a 5xx status) are logged at error level, everything else at info.
+// The record is correlated with the span in ctx.
+func (l *Logger) Request(ctx context.Context, req, resp any, err error, duration time.Duration) {
+	method := requestMethod(req)
+
+	attrs := []any{Attr("request.method", method)}
+	level := log.InfoLevel
+
+	if r, ok := resp.(*http.Response); ok && r != nil {
+		attrs = append(attrs, Attr("response.status", r.StatusCode))
+		if r.StatusCode >= http.StatusInternalServerError {
+			level = log.ErrorLevel
+		}
+	} else if err != nil {
+		attrs = append(attrs, Attr("response.status", "error"))
+	} else {
+		attrs = append(attrs, Attr("response.status", "ok"))
+	}
+
+	attrs = append(attrs, Attr("duration_ms", float64(duration.Microseconds())/1000))
+	if err != nil {
+		attrs = append(attrs, Attr("error", err.Error()))
+		level = log.ErrorLevel
+	}
+
+	for _, kv := range prefixedTagAttributes("request.", req, l.autoAttributes) {
+		attrs = append(attrs, kv)
+	}
+	for _, kv := range prefixedTagAttributes("response.", resp, l.autoAttributes) {
+		attrs = append(attrs, kv)
+	}
+
+	l.WithContext(ctx).log(level, method, attrs...)
+}
+
+// requestMethod names a request: "METHOD /path" for *http.Request, otherwise the type name
+func requestMethod(req any) string {
+	if r, ok := req.(*http.Request); ok && r != nil {
+		return r.Method + " " + r.URL.Path
+	}
+
+	rt := reflect.TypeOf(req)
+	if rt == nil {
+		return "request"
+	}
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Name() == "" {
+		return "request"
+	}
+	return rt.Name()
+}
+
+// prefixedTagAttributes returns the `pulse:"attribute:key"` fields of a struct (or pointer to struct)
+// with the prefix added to each key, plus untagged fields if auto is set (see LogOptions.AutoAttributes)
+func prefixedTagAttributes(prefix string, v any, auto bool) []KeyValue {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	attrs := tagAttributes(rv, auto)
+	for i := range attrs {
+		attrs[i].Key = prefix + attrs[i].Key
+	}
+	return attrs
+}
@@ -1,14 +1,17 @@
 package logging
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/machanirobotics/pulse/go/internal/tags"
 	"github.com/machanirobotics/pulse/go/options"
 	otellog "go.opentelemetry.io/otel/log"
 )
@@ -18,6 +21,17 @@ func formatPrefix(serviceOpts options.ServiceOptions) string {
 	return fmt.Sprintf("%s (%s | %s)", serviceOpts.Name, serviceOpts.Version, serviceOpts.Environment)
 }
 
+// resolvePrefix determines the logger prefix from the logging options.
+func resolvePrefix(serviceOpts options.ServiceOptions, opts options.LoggingOptions) string {
+	if opts.Log.DisablePrefix {
+		return ""
+	}
+	if opts.Log.Prefix != "" {
+		return opts.Log.Prefix
+	}
+	return formatPrefix(serviceOpts)
+}
+
 // resolveTimeFormat determines the appropriate time format string.
 func resolveTimeFormat(opts options.LoggingOptions) string {
 	switch opts.Log.TimeFormatKey {
@@ -62,12 +76,39 @@ func resolveCallerOffset(opts options.LoggingOptions) int {
 	return 2
 }
 
-// extractStructTagAttributes extracts attributes from struct fields with `pulse:"attribute:key_name"` tags
-func extractStructTagAttributes(rv reflect.Value) []otellog.KeyValue {
+// resolveFatalExitCode returns the exit code used by Fatal/Fatalf (default: 1)
+func resolveFatalExitCode(opts options.LoggingOptions) int {
+	if opts.Log.FatalExitCode != 0 {
+		return opts.Log.FatalExitCode
+	}
+	return 1
+}
+
+// extractStructTagAttributes extracts attributes from struct fields with `pulse:"attribute:key_name"` tags,
+// plus the extraction time and struct type name.
+// If auto is set, fields without a pulse tag are extracted too, keyed by autoAttributeKey.
+func extractStructTagAttributes(rv reflect.Value, auto bool) []otellog.KeyValue {
 	if rv.Kind() != reflect.Struct {
 		return nil
 	}
 
+	attrs := tagAttributes(rv, auto)
+	rt := rv.Type()
+
+	// Add dynamic/computed attributes
+	// Example: Add a timestamp if not present
+	attrs = append(attrs, otellog.Int64("extracted_at", time.Now().Unix()))
+
+	// Example: Add struct type name
+	attrs = append(attrs, otellog.String("struct_type", rt.Name()))
+
+	return attrs
+}
+
+// tagAttributes returns the attributes of struct fields with `pulse:"attribute:key_name"` tags,
+// and of untagged fields if auto is set (see LogOptions.AutoAttributes).
+// Fields of embedded structs are included as if they were declared in rv.
+func tagAttributes(rv reflect.Value, auto bool) []otellog.KeyValue {
 	attrs := []otellog.KeyValue{}
 	rt := rv.Type()
 
@@ -80,35 +121,48 @@ func extractStructTagAttributes(rv reflect.Value) []otellog.KeyValue {
 			continue
 		}
 
-		// Check for pulse struct tag
-		tag := field.Tag.Get("pulse")
-		if tag == "" {
+		if embedded, ok := tags.Embedded(field, fieldValue); ok {
+			attrs = append(attrs, tagAttributes(embedded, auto)...)
 			continue
 		}
 
-		// Parse tag format: "attribute:key_name"
-		if strings.HasPrefix(tag, "attribute:") {
-			attrName := strings.TrimPrefix(tag, "attribute:")
-			if attrName != "" {
-				// Convert field value to appropriate OTEL attribute
-				attrs = append(attrs, convertToOtelKeyValue(attrName, fieldValue.Interface()))
+		// Parse tag format: "attribute:key_name" (malformed tags are skipped, see tags.ValidateStruct)
+		tag, ok, err := tags.Lookup(field)
+		if !ok && auto {
+			if key, ok := autoAttributeKey(field); ok {
+				attrs = append(attrs, convertToOtelKeyValue(key, fieldValue.Interface()))
 			}
+			continue
+		}
+		if !ok || err != nil || tag.Kind != tags.KindAttribute {
+			continue
 		}
-	}
-
-	// Add dynamic/computed attributes
-	// Example: Add a timestamp if not present
-	attrs = append(attrs, otellog.Int64("extracted_at", time.Now().Unix()))
 
-	// Example: Add struct type name
-	attrs = append(attrs, otellog.String("struct_type", rt.Name()))
+		// Convert field value to appropriate OTEL attribute
+		attrs = append(attrs, convertToOtelKeyValue(tag.Name, fieldValue.Interface()))
+	}
 
 	return attrs
 }
 
+// autoAttributeKey returns the attribute key of a field without a pulse tag (LogOptions.AutoAttributes):
+// the json tag name, or the field name if there is none. Returns ok=false for fields excluded with `json:"-"`.
+func autoAttributeKey(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
 // dataToOtelAttributes converts various data types to OpenTelemetry KeyValue attributes
 // It extracts struct tags with format `pulse:"attribute:key_name"` and adds them as attributes
-func dataToOtelAttributes(v any) []otellog.KeyValue {
+// (and untagged fields if auto is set, see LogOptions.AutoAttributes)
+func dataToOtelAttributes(v any, auto bool) []otellog.KeyValue {
 	if v == nil {
 		return nil
 	}
@@ -128,7 +182,7 @@ func dataToOtelAttributes(v any) []otellog.KeyValue {
 
 	// Extract struct tag attributes if it's a struct
 	if rv.Kind() == reflect.Struct {
-		attrs = append(attrs, extractStructTagAttributes(rv)...)
+		attrs = append(attrs, extractStructTagAttributes(rv, auto)...)
 	}
 
 	// For all types, convert to JSON string and send as "data" attribute
@@ -150,6 +204,31 @@ func dataToOtelAttributes(v any) []otellog.KeyValue {
 	return attrs
 }
 
+// structAttributes returns the struct tag attributes of v (see extractStructTagAttributes),
+// or nil if v is not a struct or a pointer to one
+func structAttributes(v any, auto bool) []otellog.KeyValue {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return extractStructTagAttributes(rv, auto)
+}
+
+// defaultAttributes converts configured default attributes to OTLP attributes, sorted by key
+func defaultAttributes(defaults map[string]interface{}) []otellog.KeyValue {
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otellog.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, convertToOtelKeyValue(k, defaults[k]))
+	}
+	return attrs
+}
+
 // convertToOtelKeyValue converts a key-value pair to an OpenTelemetry KeyValue
 func convertToOtelKeyValue(key string, value any) otellog.KeyValue {
 	if value == nil {
@@ -163,10 +242,25 @@ func convertToOtelKeyValue(key string, value any) otellog.KeyValue {
 		if rv.IsNil() {
 			return otellog.String(key, "<nil>")
 		}
+	}
+
+	original := value
+	if rv.Kind() == reflect.Ptr {
 		rv = rv.Elem()
 		value = rv.Interface()
 	}
 
+	// Numeric and string kinds keep their type, even with a String method (time.Duration, enums)
+	switch rv.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+	default:
+		if kv, ok := marshaledKeyValue(key, original); ok {
+			return kv
+		}
+	}
+
 	switch rv.Kind() {
 	case reflect.String:
 		return otellog.String(key, rv.String())
@@ -181,7 +275,7 @@ func convertToOtelKeyValue(key string, value any) otellog.KeyValue {
 	case reflect.Slice, reflect.Array:
 		// Check if it's a byte slice
 		if rv.Type().Elem().Kind() == reflect.Uint8 {
-			return otellog.Bytes(key, value.([]byte))
+			return otellog.Bytes(key, byteValue(rv))
 		}
 		// For other slices, convert to JSON string
 		if b, err := json.Marshal(value); err == nil {
@@ -193,12 +287,130 @@ func convertToOtelKeyValue(key string, value any) otellog.KeyValue {
 		if b, err := json.Marshal(value); err == nil {
 			return otellog.String(key, string(b))
 		}
+		// Maps whose keys JSON does not support (e.g. structs) are marshaled with string keys
+		if rv.Kind() == reflect.Map {
+			if b, err := json.Marshal(stringKeyed(rv)); err == nil {
+				return otellog.String(key, string(b))
+			}
+		}
 		return otellog.String(key, fmt.Sprintf("%+v", value))
 	default:
 		return otellog.String(key, fmt.Sprintf("%+v", value))
 	}
 }
 
+// marshaledKeyValue converts custom types (IDs, structs) by the canonical form they define: TextMarshaler
+// (uuid.UUID, net.IP, time.Time, ...), Stringer or json.Marshaler. Returns ok=false if none is implemented.
+func marshaledKeyValue(key string, value any) (otellog.KeyValue, bool) {
+	switch v := value.(type) {
+	case encoding.TextMarshaler:
+		if b, err := v.MarshalText(); err == nil {
+			return otellog.String(key, string(b)), true
+		}
+		return otellog.String(key, fmt.Sprint(value)), true
+	case fmt.Stringer:
+		return otellog.String(key, v.String()), true
+	case json.Marshaler:
+		if b, err := v.MarshalJSON(); err == nil {
+			return otellog.String(key, string(b)), true
+		}
+	}
+	return otellog.KeyValue{}, false
+}
+
+// consoleData returns the console key/value pairs of the primary log data: its top-level fields
+// if LogOptions.FlattenData is set and it is a struct or map, a single "data" pair otherwise.
+func (l *Logger) consoleData(v any) []interface{} {
+	if l.flattenData {
+		if keyvals, ok := flattenedData(v); ok {
+			return keyvals
+		}
+	}
+	return []interface{}{"data", formattedData(v)}
+}
+
+// flattenedData returns the top-level fields of a struct (in declaration order, keyed like
+// autoAttributeKey, with embedded struct fields inlined) or the entries of a map (sorted by key)
+// as console key/value pairs. Nested structs, maps and slices are rendered as compact JSON.
+// Returns ok=false for other kinds of values.
+func flattenedData(v any) ([]interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return structKeyvals(rv), true
+	case reflect.Map:
+		keyvals := make([]interface{}, 0, 2*rv.Len())
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return mapKeyString(keys[i]) < mapKeyString(keys[j])
+		})
+		for _, key := range keys {
+			keyvals = append(keyvals, mapKeyString(key), compactValue(rv.MapIndex(key).Interface()))
+		}
+		return keyvals, true
+	default:
+		return nil, false
+	}
+}
+
+// structKeyvals returns the exported fields of rv as console key/value pairs (see flattenedData)
+func structKeyvals(rv reflect.Value) []interface{} {
+	var keyvals []interface{}
+	rt := rv.Type()
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		fieldValue := rv.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if embedded, ok := tags.Embedded(field, fieldValue); ok {
+			keyvals = append(keyvals, structKeyvals(embedded)...)
+			continue
+		}
+
+		if key, ok := autoAttributeKey(field); ok {
+			keyvals = append(keyvals, key, compactValue(fieldValue.Interface()))
+		}
+	}
+
+	return keyvals
+}
+
+// compactValue renders structs, maps and slices (except fmt.Stringers) as single-line JSON
+// for flattened console output
+func compactValue(v any) any {
+	if _, ok := v.(fmt.Stringer); ok {
+		return v // e.g. time.Time, printed as is by the console logger
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(byteValue(rv))
+		}
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+		return fmt.Sprintf("%+v", v)
+	default:
+		return v
+	}
+}
+
 // formattedData attempts to marshal structs, maps, or slices into
 // pretty-printed JSON for console output. Fallbacks to fmt-compatible output for others.
 func formattedData(v any) any {
@@ -224,13 +436,18 @@ func formattedData(v any) any {
 		if b, err := json.MarshalIndent(v, "", "  "); err == nil {
 			return string(b)
 		}
+		if rv.Kind() == reflect.Map {
+			if b, err := json.MarshalIndent(stringKeyed(rv), "", "  "); err == nil {
+				return string(b)
+			}
+		}
 		// Fallback to default formatting if marshal fails
 		return fmt.Sprintf("%+v", v)
 
 	case reflect.Slice, reflect.Array:
 		// Check if it's a byte slice
 		if rv.Type().Elem().Kind() == reflect.Uint8 {
-			return string(v.([]byte))
+			return string(byteValue(rv))
 		}
 		// Marshal other slices/arrays into JSON
 		if b, err := json.MarshalIndent(v, "", "  "); err == nil {
@@ -258,6 +475,24 @@ func formattedData(v any) any {
 	}
 }
 
+// byteValue returns the bytes of a slice or array of bytes, including named types
+// (e.g. json.RawMessage, [16]byte). Arrays are copied, since they may not be addressable.
+func byteValue(rv reflect.Value) []byte {
+	if rv.Kind() == reflect.Slice {
+		return rv.Bytes()
+	}
+
+	b := make([]byte, rv.Len())
+	if rv.Type().Elem() == reflect.TypeOf(byte(0)) {
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return b
+	}
+	for i := range b { // Named element type (type B uint8), which reflect.Copy does not convert
+		b[i] = byte(rv.Index(i).Uint())
+	}
+	return b
+}
+
 // convertToMap converts any value to a map[string]interface{} for MCAP logging
 func convertToMap(v any) map[string]interface{} {
 	if v == nil {
@@ -277,12 +512,7 @@ func convertToMap(v any) map[string]interface{} {
 
 	// If already a map, try to convert it
 	if rv.Kind() == reflect.Map {
-		result := make(map[string]interface{})
-		for _, key := range rv.MapKeys() {
-			keyStr := fmt.Sprintf("%v", key.Interface())
-			result[keyStr] = rv.MapIndex(key).Interface()
-		}
-		return result
+		return stringKeyed(rv)
 	}
 
 	// For structs, marshal to JSON and unmarshal to map
@@ -317,3 +547,48 @@ func getCallerInfo(skip int) (string, int) {
 
 	return file, line
 }
+
+// stringKeyed returns a copy of a map with its keys converted by mapKeyString, so it can be marshaled
+// to JSON whatever the key type. Nested maps are converted too, and values JSON cannot marshal
+// (e.g. funcs) are formatted with fmt. encoding/json sorts the keys, so the output is deterministic.
+func stringKeyed(rv reflect.Value) map[string]interface{} {
+	result := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		result[mapKeyString(iter.Key())] = jsonValue(iter.Value())
+	}
+	return result
+}
+
+// jsonValue returns a map value that can be marshaled to JSON (see stringKeyed)
+func jsonValue(rv reflect.Value) interface{} {
+	for rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() == reflect.Map && !rv.IsNil() {
+		return stringKeyed(rv)
+	}
+
+	value := rv.Interface()
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+	return value
+}
+
+// mapKeyString formats a map key: strings as is, encoding.TextMarshaler keys in their text form
+// (as encoding/json does) and anything else (numbers, structs) with %+v
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if b, err := marshaler.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%+v", key.Interface())
+}
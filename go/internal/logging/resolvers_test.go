@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+type BaseRequest struct {
+	RequestID string `pulse:"attribute:request.id"`
+}
+
+type createOrderRequest struct {
+	BaseRequest
+	OrderID string `pulse:"attribute:order.id"`
+}
+
+func TestTagAttributesEmbedded(t *testing.T) {
+	req := createOrderRequest{BaseRequest: BaseRequest{RequestID: "req-1"}, OrderID: "42"}
+
+	got := make(map[string]string)
+	for _, kv := range tagAttributes(reflect.ValueOf(req), false) {
+		got[kv.Key] = kv.Value.AsString()
+	}
+	want := map[string]string{"request.id": "req-1", "order.id": "42"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tagAttributes() = %v, want %v", got, want)
+	}
+}
+
+type gridCell struct {
+	X, Y int
+}
+
+func TestConvertToOtelKeyValueMapKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "int keys",
+			value: map[int]string{2: "b", 1: "a", 10: "j"},
+			want:  `{"1":"a","10":"j","2":"b"}`,
+		},
+		{
+			name:  "struct keys",
+			value: map[gridCell]string{{X: 1, Y: 2}: "wall", {X: 0, Y: 0}: "start"},
+			want:  `{"{X:0 Y:0}":"start","{X:1 Y:2}":"wall"}`,
+		},
+		{
+			name:  "nested map with struct keys",
+			value: map[string]any{"grid": map[gridCell]int{{X: 3, Y: 4}: 7}},
+			want:  `{"grid":{"{X:3 Y:4}":7}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Marshaled twice to check the output is deterministic whatever the map iteration order
+			for i := 0; i < 2; i++ {
+				if got := convertToOtelKeyValue("cells", tt.value).Value.AsString(); got != tt.want {
+					t.Errorf("convertToOtelKeyValue() = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertToMapStructKeys(t *testing.T) {
+	got := convertToMap(map[gridCell]int{{X: 1, Y: 2}: 5})
+	want := map[string]interface{}{"{X:1 Y:2}": 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertToMap() = %v, want %v", got, want)
+	}
+}
+
+func TestFormattedDataIntKeys(t *testing.T) {
+	got := formattedData(map[int]string{1: "a"})
+	want := "{\n  \"1\": \"a\"\n}"
+	if got != want {
+		t.Errorf("formattedData() = %q, want %q", got, want)
+	}
+}
+
+type checksum []byte
+
+type flag uint8
+
+func TestConvertToOtelKeyValueBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{name: "byte slice", value: []byte{1, 2, 3}},
+		{name: "named byte slice", value: checksum{1, 2, 3}},
+		{name: "byte array", value: [3]byte{1, 2, 3}},
+		{name: "pointer to byte array", value: &[3]byte{1, 2, 3}},
+		{name: "array of named bytes", value: [3]flag{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertToOtelKeyValue("digest", tt.value).Value.AsBytes()
+			if !reflect.DeepEqual(got, []byte{1, 2, 3}) {
+				t.Errorf("convertToOtelKeyValue() = %v, want [1 2 3]", got)
+			}
+		})
+	}
+}
+
+type priority int
+
+func (p priority) String() string { return [...]string{"low", "high"}[p] }
+
+type orderID struct{ n int }
+
+func (o orderID) String() string { return "order-42" }
+
+func TestConvertToOtelKeyValueKinds(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  otellog.Value
+	}{
+		{name: "duration stays numeric", value: 2 * time.Second, want: otellog.Int64Value(int64(2 * time.Second))},
+		{name: "named enum stays numeric", value: priority(1), want: otellog.Int64Value(1)},
+		{name: "stringer struct", value: orderID{n: 42}, want: otellog.StringValue("order-42")},
+		{name: "text marshaler slice", value: net.IPv4(10, 0, 0, 1), want: otellog.StringValue("10.0.0.1")},
+		{name: "text marshaler struct", value: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), want: otellog.StringValue("2026-01-02T03:04:05Z")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertToOtelKeyValue("key", tt.value).Value
+			if !got.Equal(tt.want) {
+				t.Errorf("convertToOtelKeyValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormattedDataBytes(t *testing.T) {
+	for _, value := range []any{[]byte("abc"), checksum("abc"), [3]byte{'a', 'b', 'c'}} {
+		if got := formattedData(value); got != "abc" {
+			t.Errorf("formattedData(%T) = %v, want abc", value, got)
+		}
+		if got := compactValue(value); got != "abc" {
+			t.Errorf("compactValue(%T) = %v, want abc", value, got)
+		}
+	}
+}
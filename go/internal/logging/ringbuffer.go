@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// LogEntry represents a single log line captured by the in-memory ring buffer
+type LogEntry struct {
+	Timestamp time.Time              `json:"timestamp"`      // Time the log was emitted
+	Level     string                 `json:"level"`          // Log level (e.g., "info", "error")
+	Message   string                 `json:"message"`        // Log message
+	File      string                 `json:"file"`           // Caller filename
+	Line      int                    `json:"line"`           // Caller line number
+	Data      map[string]interface{} `json:"data,omitempty"` // Additional structured data
+}
+
+// logRingBuffer keeps the most recent log entries in memory with a fixed capacity
+type logRingBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int  // Index of the slot to write next
+	full    bool // Whether the buffer has wrapped around
+}
+
+// newLogRingBuffer creates a ring buffer holding up to size entries
+func newLogRingBuffer(size int) *logRingBuffer {
+	return &logRingBuffer{
+		entries: make([]LogEntry, size),
+	}
+}
+
+// add stores an entry, overwriting the oldest one when the buffer is full
+func (r *logRingBuffer) add(entry LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns a copy of the stored entries ordered from oldest to newest
+func (r *logRingBuffer) snapshot() []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		result := make([]LogEntry, r.next)
+		copy(result, r.entries[:r.next])
+		return result
+	}
+
+	result := make([]LogEntry, 0, len(r.entries))
+	result = append(result, r.entries[r.next:]...)
+	result = append(result, r.entries[:r.next]...)
+	return result
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// originalKeysAttribute is the OTLP attribute that maps sanitized keys back to the original
+// ones (LoggingOptions.SanitizeAttributeKeys), as a JSON object such as {"user_id":"user.id"}
+const originalKeysAttribute = "pulse.original_keys"
+
+// sanitizeKey replaces every character of key other than ASCII letters, digits and '_' with '_',
+// and prefixes a leading digit with '_', so the key is a valid Loki label name
+func sanitizeKey(key string) string {
+	var b strings.Builder
+	b.Grow(len(key) + 1)
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// sanitizeAttributes renames the keys of attrs[from:] with sanitizeKey and, if any key changed,
+// appends the pulse.original_keys attribute with the sanitized -> original mapping.
+// The attributes before from (service and caller semantic conventions) keep their names.
+// A sanitized key that is already used by another attribute (user.id and user_id in one record)
+// gets a numeric suffix (user_id_2), so neither value is lost; repeats of the same original key
+// get the same name, so the precedence of later attributes is kept.
+func sanitizeAttributes(attrs []otellog.KeyValue, from int) []otellog.KeyValue {
+	// Keys that are not renamed are taken first, whatever their position
+	taken := make(map[string]bool, len(attrs))
+	for i, attr := range attrs {
+		if i < from || sanitizeKey(attr.Key) == attr.Key {
+			taken[attr.Key] = true
+		}
+	}
+
+	var original map[string]string // sanitized -> original
+	renamed := make(map[string]string)
+	for i := from; i < len(attrs); i++ {
+		attr := attrs[i]
+		base := sanitizeKey(attr.Key)
+		if base == attr.Key {
+			continue
+		}
+		key, ok := renamed[attr.Key]
+		if !ok {
+			key = base
+			for n := 2; taken[key]; n++ {
+				key = fmt.Sprintf("%s_%d", base, n)
+			}
+			taken[key] = true
+			renamed[attr.Key] = key
+		}
+		if original == nil {
+			original = make(map[string]string)
+		}
+		original[key] = attr.Key
+		attrs[i].Key = key
+	}
+	if original == nil {
+		return attrs
+	}
+
+	mapping, _ := json.Marshal(original) // Cannot fail for a map of strings; keys are sorted
+	return append(attrs, otellog.String(originalKeysAttribute, string(mapping)))
+}
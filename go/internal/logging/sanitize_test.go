@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+func TestSanitizeKey(t *testing.T) {
+	tests := map[string]string{
+		"user_id":     "user_id",
+		"user.id":     "user_id",
+		"auth-method": "auth_method",
+		"2fa":         "_2fa",
+		"über":        "_ber",
+	}
+	for key, want := range tests {
+		if got := sanitizeKey(key); got != want {
+			t.Errorf("sanitizeKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestSanitizeAttributes(t *testing.T) {
+	attrs := []otellog.KeyValue{
+		otellog.String("service.name", "test"), // Built-in, kept as is
+		otellog.String("user.id", "alice"),
+		otellog.String("user_id", "42"),
+		otellog.String("user.id", "bob"), // Later value of the same key, same name
+		otellog.String("request-id", "r1"),
+	}
+
+	got := sanitizeAttributes(attrs, 1)
+
+	want := []string{"service.name", "user_id_2", "user_id", "user_id_2", "request_id", originalKeysAttribute}
+	if len(got) != len(want) {
+		t.Fatalf("got %d attributes, want %d", len(got), len(want))
+	}
+	for i, key := range want {
+		if got[i].Key != key {
+			t.Errorf("attrs[%d] = %s, want %s", i, got[i].Key, key)
+		}
+	}
+	mapping := `{"request_id":"request-id","user_id_2":"user.id"}`
+	if value := got[len(got)-1].Value.AsString(); value != mapping {
+		t.Errorf("%s = %s, want %s", originalKeysAttribute, value, mapping)
+	}
+}
+
+func TestSanitizeAttributesUnchanged(t *testing.T) {
+	attrs := []otellog.KeyValue{otellog.String("user_id", "alice")}
+	if got := sanitizeAttributes(attrs, 0); len(got) != 1 {
+		t.Errorf("got %d attributes, want 1 (no mapping for unchanged keys)", len(got))
+	}
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// logVolumeMetric is the counter of emitted log records, by level name
+const logVolumeMetric = "pulse.logs.emitted"
+
+// logVolume counts emitted log records per level, e.g. to spot log-spam regressions
+type logVolume struct {
+	counter metric.Int64Counter
+	options sync.Map // Level name -> []metric.AddOption, so counting does not allocate
+}
+
+// newLogVolume creates the log volume counter. Returns nil if metrics are not available.
+func newLogVolume(meter *telemetry.Metrics) *logVolume {
+	if meter == nil {
+		return nil
+	}
+
+	counter, err := meter.Counter(logVolumeMetric, metric.WithDescription("Log records emitted, by level"))
+	if err != nil {
+		fmt.Printf("Warning: Failed to create %s counter: %v\n", logVolumeMetric, err)
+		return nil
+	}
+	return &logVolume{counter: counter}
+}
+
+// add counts one record at the named level
+func (v *logVolume) add(ctx context.Context, level string) {
+	if v == nil {
+		return
+	}
+
+	opts, ok := v.options.Load(level)
+	if !ok {
+		set := attribute.NewSet(attribute.String("level", level))
+		opts, _ = v.options.LoadOrStore(level, []metric.AddOption{metric.WithAttributeSet(set)})
+	}
+	v.counter.Add(ctx, 1, opts.([]metric.AddOption)...)
+}
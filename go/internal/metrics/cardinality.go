@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// overflowAttribute matches the attribute the OpenTelemetry SDK uses for its cardinality overflow series
+var overflowAttribute = attribute.Bool("otel.metric.overflow", true)
+
+// cardinalityGuard limits the number of distinct attribute sets recorded per metric.
+// Once a metric reaches the limit, new attribute sets collapse into the overflow series.
+type cardinalityGuard struct {
+	limit  int
+	mu     sync.Mutex
+	seen   map[string]map[attribute.Distinct]struct{} // metric name -> attribute sets seen
+	warned map[string]bool                            // metric name -> overflow warning already printed
+}
+
+// newCardinalityGuard creates a guard with the given limit. Returns nil if limit is not positive.
+func newCardinalityGuard(limit int) *cardinalityGuard {
+	if limit <= 0 {
+		return nil
+	}
+	return &cardinalityGuard{
+		limit:  limit,
+		seen:   make(map[string]map[attribute.Distinct]struct{}),
+		warned: make(map[string]bool),
+	}
+}
+
+// apply returns the labels to record for the metric, replacing them with the overflow
+// attribute if recording them would exceed the limit. One slot is reserved for the
+// overflow series, matching the SDK's cardinality limit semantics.
+func (g *cardinalityGuard) apply(name string, labels []attribute.KeyValue) []attribute.KeyValue {
+	if g == nil {
+		return labels
+	}
+
+	set := attribute.NewSet(labels...)
+	if g.admit(name, set.Equivalent()) {
+		return labels
+	}
+	return []attribute.KeyValue{overflowAttribute}
+}
+
+// applySet is apply for a pre-built attribute set (Metrics.AddInt64)
+func (g *cardinalityGuard) applySet(name string, set attribute.Set) attribute.Set {
+	if g == nil || g.admit(name, set.Equivalent()) {
+		return set
+	}
+	return attribute.NewSet(overflowAttribute)
+}
+
+// admit reports whether the attribute set may be recorded for the metric, remembering new sets
+// while the limit allows
+func (g *cardinalityGuard) admit(name string, key attribute.Distinct) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sets, exists := g.seen[name]
+	if !exists {
+		sets = make(map[attribute.Distinct]struct{})
+		g.seen[name] = sets
+	}
+
+	if _, ok := sets[key]; ok {
+		return true
+	}
+	if len(sets) < g.limit-1 {
+		sets[key] = struct{}{}
+		return true
+	}
+
+	if !g.warned[name] {
+		g.warned[name] = true
+		fmt.Printf("Warning: metric %s exceeded cardinality limit of %d, new attribute sets are recorded as overflow\n", name, g.limit)
+	}
+	return false
+}
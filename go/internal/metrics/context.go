@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextLabels returns the dimensions taken from ctx for the configured keys
+// (MetricsTelemetryOptions.ContextAttributes): the attribute of the span in ctx with that key,
+// or else the baggage member. Keys found in neither are left out.
+func contextLabels(ctx context.Context, keys []string) []attribute.KeyValue {
+	if len(keys) == 0 || ctx == nil {
+		return nil
+	}
+
+	// Recording SDK spans expose their attributes; other spans only contribute baggage
+	var spanAttrs []attribute.KeyValue
+	if span, ok := trace.SpanFromContext(ctx).(sdktrace.ReadOnlySpan); ok {
+		spanAttrs = span.Attributes()
+	}
+	bag := baggage.FromContext(ctx)
+
+	labels := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		if kv, ok := findAttribute(spanAttrs, key); ok {
+			labels = append(labels, kv)
+			continue
+		}
+		if member := bag.Member(key); member.Key() != "" {
+			labels = append(labels, attribute.String(key, member.Value()))
+		}
+	}
+	return labels
+}
+
+// findAttribute returns the last attribute with the key (span attributes set later win)
+func findAttribute(attrs []attribute.KeyValue, key string) (attribute.KeyValue, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if string(attrs[i].Key) == key {
+			return attrs[i], true
+		}
+	}
+	return attribute.KeyValue{}, false
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/machanirobotics/pulse/go/internal/tags"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// maxFastOptions bounds the attribute sets whose measurement options fastCache keeps. Without a
+// CardinalityLimit the number of sets is unbounded; sets beyond this are still recorded, uncached.
+const maxFastOptions = 4096
+
+// fastCache caches the instruments and measurement options used by AddInt64, shared with
+// derived instances. Options are cached per attribute set because building them allocates;
+// at most maxFastOptions sets are cached.
+type fastCache struct {
+	counters sync.Map // metric name -> metric.Float64Counter
+
+	mu      sync.RWMutex
+	options map[attribute.Distinct][]metric.AddOption
+}
+
+// newFastCache creates an empty cache
+func newFastCache() *fastCache {
+	return &fastCache{options: make(map[attribute.Distinct][]metric.AddOption)}
+}
+
+// addOptions returns the cached measurement options for the attribute set
+func (c *fastCache) addOptions(set attribute.Set) []metric.AddOption {
+	key := set.Equivalent()
+
+	c.mu.RLock()
+	opts, ok := c.options[key]
+	c.mu.RUnlock()
+	if ok {
+		return opts
+	}
+
+	opts = []metric.AddOption{metric.WithAttributeSet(set)}
+	c.mu.Lock()
+	if len(c.options) < maxFastOptions {
+		c.options[key] = opts
+	}
+	c.mu.Unlock()
+	return opts
+}
+
+// AttributeSet builds an attribute set for AddInt64 from the default attributes followed by attrs
+// (later keys win). Build it once and reuse it; building a set allocates.
+func (m *Metrics) AttributeSet(attrs ...attribute.KeyValue) attribute.Set {
+	labels := append(append([]attribute.KeyValue(nil), m.defaults...), attrs...)
+	return attribute.NewSet(labels...)
+}
+
+// AddInt64 adds v to the named counter with a pre-built attribute set (see AttributeSet).
+// It is the fast path for very hot counters: no reflection, no tag parsing and a cached instrument,
+// so the OTLP path does not allocate per call. The set is used as is; default and context
+// attributes are only included if the set was built with AttributeSet. The counter is the same
+// instrument a `pulse:"metric:counter:name"` field records to. If MCAP is enabled, the value is
+// also written there, which does allocate.
+func (m *Metrics) AddInt64(name string, v int64, attrs attribute.Set) error {
+	attrs = m.cardinality.applySet(name, attrs)
+
+	if m.otelMetrics != nil {
+		counter, err := m.cachedCounter(name)
+		if err != nil {
+			return err
+		}
+		counter.Add(m.ctx, float64(v), m.fast.addOptions(attrs)...)
+	}
+
+	if m.mcapWriter != nil {
+		labels := make(map[string]string, attrs.Len())
+		for _, kv := range attrs.ToSlice() {
+			labels[string(kv.Key)] = kv.Value.Emit()
+		}
+		return m.mcapWriter.WriteCounter(name, float64(v), labels, m.now())
+	}
+	return nil
+}
+
+// cachedCounter returns the counter for name, creating and registering it on first use
+func (m *Metrics) cachedCounter(name string) (metric.Float64Counter, error) {
+	if counter, ok := m.fast.counters.Load(name); ok {
+		return counter.(metric.Float64Counter), nil
+	}
+
+	if err := m.instruments.register(name, tags.MetricCounter, "AddInt64"); err != nil {
+		return nil, err
+	}
+	counter, err := m.otelMetrics.FloatCounter(name)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := m.fast.counters.LoadOrStore(name, counter)
+	return actual.(metric.Float64Counter), nil
+}
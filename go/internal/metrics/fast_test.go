@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestAddInt64(t *testing.T) {
+	m, reader := newTestMetrics(t)
+	set := m.AttributeSet(attribute.String("model", "small"))
+
+	for i := 0; i < 3; i++ {
+		if err := m.AddInt64("tokens.processed", 100, set); err != nil {
+			t.Fatalf("AddInt64() error = %v", err)
+		}
+	}
+
+	sum, ok := collectMetric(t, reader, "tokens.processed").Data.(metricdata.Sum[float64])
+	if !ok || len(sum.DataPoints) != 1 {
+		t.Fatalf("tokens.processed is not a counter with one data point")
+	}
+	if got := sum.DataPoints[0].Value; got != 300 {
+		t.Errorf("tokens.processed = %v, want 300", got)
+	}
+	if got, _ := sum.DataPoints[0].Attributes.Value("model"); got.AsString() != "small" {
+		t.Errorf("model = %q, want small", got.AsString())
+	}
+}
+
+func TestAddInt64DoesNotAllocate(t *testing.T) {
+	m, _ := newTestMetrics(t)
+	set := m.AttributeSet(attribute.String("model", "small"))
+	_ = m.AddInt64("tokens.processed", 1, set) // Create the instrument and cache the options
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		_ = m.AddInt64("tokens.processed", 1, set)
+	})
+	if allocs > 0 {
+		t.Errorf("AddInt64 allocates %v times per call, want 0", allocs)
+	}
+}
+
+func TestFastCacheBounded(t *testing.T) {
+	c := newFastCache()
+	for i := 0; i < maxFastOptions+10; i++ {
+		set := attribute.NewSet(attribute.String("user.id", strconv.Itoa(i)))
+		if opts := c.addOptions(set); len(opts) != 1 {
+			t.Fatalf("addOptions() returned %d options, want 1", len(opts))
+		}
+	}
+	if len(c.options) != maxFastOptions {
+		t.Errorf("cached %d attribute sets, want at most %d", len(c.options), maxFastOptions)
+	}
+}
+
+func BenchmarkAddInt64(b *testing.B) {
+	m, _ := newTestMetrics(b)
+	set := m.AttributeSet(attribute.String("model", "small"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = m.AddInt64("tokens.processed", 1, set)
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// defaultGaugeInterval is how often observed gauges are written to MCAP without an export interval
+const defaultGaugeInterval = 10 * time.Second
+
+// errMetricsClosed is returned by RegisterGauge after Close
+var errMetricsClosed = errors.New("metrics are closed")
+
+// observedGauges tracks the callbacks registered with RegisterGauge so Close can stop them.
+// It is shared with instances derived by WithContext.
+type observedGauges struct {
+	interval time.Duration // MCAP write interval (the metric export interval)
+
+	mu            sync.Mutex
+	registrations []metric.Registration
+	stop          chan struct{}
+	wg            sync.WaitGroup
+	closed        bool
+}
+
+// newObservedGauges creates the gauge tracker, writing to MCAP every exportIntervalSeconds
+func newObservedGauges(exportIntervalSeconds int) *observedGauges {
+	interval := time.Duration(exportIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultGaugeInterval
+	}
+	return &observedGauges{interval: interval, stop: make(chan struct{})}
+}
+
+// RegisterGauge registers a gauge whose value is read by calling observe on each collection,
+// e.g. the current size of a worker pool. The default attributes and attrs are its dimensions.
+// If MCAP is enabled, the value is also observed and written there every export interval.
+// The callback runs until Close. Prefer it over `pulse:"metric:gauge:name"` fields for state
+// that is read rather than pushed.
+func (m *Metrics) RegisterGauge(name string, observe func(context.Context) float64, attrs ...attribute.KeyValue) error {
+	if err := m.instruments.register(name, observableGauge, "RegisterGauge"); err != nil {
+		return err
+	}
+	set := attribute.NewSet(append(append([]attribute.KeyValue(nil), m.defaults...), attrs...)...)
+
+	g := m.gauges
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return errMetricsClosed
+	}
+
+	if m.otelMetrics != nil {
+		gauge, err := m.otelMetrics.FloatGauge(name)
+		if err != nil {
+			return err
+		}
+		registration, err := m.otelMetrics.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+			o.ObserveFloat64(gauge, observe(ctx), metric.WithAttributeSet(set))
+			return nil
+		}, gauge)
+		if err != nil {
+			return err
+		}
+		g.registrations = append(g.registrations, registration)
+	}
+
+	if m.mcapWriter != nil {
+		labels := make(map[string]string, set.Len())
+		for _, kv := range set.ToSlice() {
+			labels[string(kv.Key)] = kv.Value.Emit()
+		}
+
+		g.wg.Add(1)
+		go func() {
+			defer g.wg.Done()
+			ticker := time.NewTicker(g.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-g.stop:
+					return
+				case <-ticker.C:
+					_ = m.mcapWriter.WriteGauge(name, observe(context.Background()), labels, m.now()) // Ignore write errors, retried next tick
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// close unregisters the gauge callbacks and stops the MCAP writers. Safe to call more than once.
+func (g *observedGauges) close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	registrations := g.registrations
+	g.registrations = nil
+	close(g.stop)
+	g.mu.Unlock()
+
+	g.wg.Wait()
+
+	var errs []error
+	for _, registration := range registrations {
+		if err := registration.Unregister(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
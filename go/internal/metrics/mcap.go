@@ -3,6 +3,7 @@ package metrics
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,17 +16,20 @@ import (
 // with dynamic channel creation per metric name
 type MetricMcapWriter struct {
 	unifiedWriter *foxglove.UnifiedMcapWriter // Shared MCAP writer
-	channels      map[string]uint16           // Map metric name to channel ID
+	channels      map[string]uint16           // Map metric topic (name + labels) to channel ID
 	mu            sync.Mutex                  // Mutex for channel map
 	serviceName   string
 	metadata      map[string]string
+	singleChannel bool                // Write all metrics to one channel (options.MetricChannelSingle)
+	summaries     *histogramSummaries // Percentile summaries of histograms (nil if disabled)
 }
 
 // FoxgloveMetric represents a metric value for Foxglove panels
 type FoxgloveMetric struct {
-	Timestamp FoxgloveTimestamp `json:"timestamp"`
-	Name      string            `json:"name"`
-	Value     float64           `json:"value"`
+	Timestamp  FoxgloveTimestamp `json:"timestamp"`
+	Name       string            `json:"name"`
+	Value      float64           `json:"value"`
+	Attributes map[string]string `json:"attributes,omitempty"` // Metric dimensions
 }
 
 // FoxgloveTimestamp represents a timestamp in Foxglove format
@@ -42,45 +46,73 @@ func NewMetricMcapWriter(serviceOpts options.ServiceOptions, unifiedWriter *foxg
 		"environment":  string(serviceOpts.Environment),
 	}
 
-	return &MetricMcapWriter{
+	foxgloveOpts := unifiedWriter.Options()
+
+	writer := &MetricMcapWriter{
 		unifiedWriter: unifiedWriter,
 		channels:      make(map[string]uint16),
 		serviceName:   serviceOpts.Name,
 		metadata:      metadata,
-	}, nil
+		singleChannel: foxgloveOpts.MetricChannelMode == options.MetricChannelSingle,
+	}
+
+	if foxgloveOpts.HistogramSummaries {
+		writer.summaries = newHistogramSummaries(time.Duration(foxgloveOpts.HistogramSummaryIntervalSeconds) * time.Second)
+	}
+
+	return writer, nil
 }
 
-// WriteCounter writes a counter metric
-func (m *MetricMcapWriter) WriteCounter(name string, value float64) error {
-	return m.writeMetric(name, value)
+// WriteCounter writes a counter metric stamped with the given time
+func (m *MetricMcapWriter) WriteCounter(name string, value float64, labels map[string]string, timestamp time.Time) error {
+	return m.writeMetric(name, value, labels, timestamp)
 }
 
-// WriteHistogram writes a histogram metric
-func (m *MetricMcapWriter) WriteHistogram(name string, value float64) error {
-	return m.writeMetric(name, value)
+// WriteHistogram writes a histogram metric stamped with the given time.
+// If histogram summaries are enabled, the percentiles of the previous window are written when a new window starts.
+func (m *MetricMcapWriter) WriteHistogram(name string, value float64, labels map[string]string, timestamp time.Time) error {
+	if err := m.writeMetric(name, value, labels, timestamp); err != nil {
+		return err
+	}
+
+	if m.summaries != nil {
+		return m.writeSummaries(m.summaries.add(name, value, labels, timestamp))
+	}
+	return nil
+}
+
+// writeSummaries writes histogram percentile summaries as separate metrics
+func (m *MetricMcapWriter) writeSummaries(points []summaryPoint) error {
+	for _, p := range points {
+		if err := m.writeMetric(p.name, p.value, p.labels, p.timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// WriteGauge writes a gauge metric
-func (m *MetricMcapWriter) WriteGauge(name string, value float64) error {
-	return m.writeMetric(name, value)
+// WriteGauge writes a gauge metric stamped with the given time
+func (m *MetricMcapWriter) WriteGauge(name string, value float64, labels map[string]string, timestamp time.Time) error {
+	return m.writeMetric(name, value, labels, timestamp)
 }
 
-// writeMetric writes a metric to MCAP with dynamic channel creation
-func (m *MetricMcapWriter) writeMetric(name string, value float64) error {
-	// Get or create channel for this metric
-	channelID, err := m.getOrCreateChannel(name)
+// writeMetric writes a metric to MCAP with dynamic channel creation.
+// Each distinct label set gets its own channel so Foxglove can distinguish series.
+func (m *MetricMcapWriter) writeMetric(name string, value float64, labels map[string]string, timestamp time.Time) error {
+	// Get or create channel for this metric and label set
+	channelID, err := m.getOrCreateChannel(name, labels)
 	if err != nil {
 		return err
 	}
 
-	now := time.Now()
 	metric := FoxgloveMetric{
 		Timestamp: FoxgloveTimestamp{
-			Sec:  uint32(now.Unix()),
-			Nsec: uint32(now.Nanosecond()),
+			Sec:  uint32(timestamp.Unix()),
+			Nsec: uint32(timestamp.Nanosecond()),
 		},
-		Name:  name,
-		Value: value,
+		Name:       name,
+		Value:      value,
+		Attributes: labels,
 	}
 
 	data, err := json.Marshal(metric)
@@ -88,21 +120,38 @@ func (m *MetricMcapWriter) writeMetric(name string, value float64) error {
 		return fmt.Errorf("failed to marshal metric: %w", err)
 	}
 
-	nowNano := uint64(now.UnixNano())
-	return m.unifiedWriter.WriteMessage(channelID, data, nowNano, nowNano)
+	logTime := uint64(timestamp.UnixNano())
+	return m.unifiedWriter.WriteMessage(channelID, data, logTime, logTime)
 }
 
-// getOrCreateChannel gets existing channel ID or creates new channel for metric
-func (m *MetricMcapWriter) getOrCreateChannel(metricName string) (uint16, error) {
+// getOrCreateChannel gets existing channel ID or creates new channel for metric and label set
+func (m *MetricMcapWriter) getOrCreateChannel(metricName string, labels map[string]string) (uint16, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if channelID, exists := m.channels[metricName]; exists {
-		return channelID, nil
+	// In single channel mode, every metric goes to /metrics/{service} and is
+	// distinguished by the name and attributes fields of the message
+	if m.singleChannel {
+		return m.getOrCreateSingleChannel()
+	}
+
+	// Sort label keys so the same label set always maps to the same channel
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
 	// Convert metric name to topic: llm.cache.hit_rate -> /metrics/{service}/llm/cache/hit_rate
+	// Labels are appended as path segments: /metrics/{service}/llm/cache/hit_rate/model=gpt-4
 	topic := fmt.Sprintf("/metrics/%s/%s", m.serviceName, strings.ReplaceAll(metricName, ".", "/"))
+	for _, k := range keys {
+		topic += fmt.Sprintf("/%s=%s", k, labels[k])
+	}
+
+	if channelID, exists := m.channels[topic]; exists {
+		return channelID, nil
+	}
 
 	// Create channel metadata
 	channelMetadata := make(map[string]string)
@@ -110,6 +159,9 @@ func (m *MetricMcapWriter) getOrCreateChannel(metricName string) (uint16, error)
 		channelMetadata[k] = v
 	}
 	channelMetadata["metric_name"] = metricName
+	for _, k := range keys {
+		channelMetadata["attribute."+k] = labels[k]
+	}
 
 	// Create channel in unified writer
 	channelID, err := m.unifiedWriter.CreateMetricChannel(topic, channelMetadata)
@@ -117,13 +169,33 @@ func (m *MetricMcapWriter) getOrCreateChannel(metricName string) (uint16, error)
 		return 0, fmt.Errorf("failed to create channel for %s: %w", metricName, err)
 	}
 
-	m.channels[metricName] = channelID
+	m.channels[topic] = channelID
+	return channelID, nil
+}
+
+// getOrCreateSingleChannel gets or creates the channel shared by all metrics. Must be called with m.mu held.
+func (m *MetricMcapWriter) getOrCreateSingleChannel() (uint16, error) {
+	topic := fmt.Sprintf("/metrics/%s", m.serviceName)
+	if channelID, exists := m.channels[topic]; exists {
+		return channelID, nil
+	}
+
+	channelID, err := m.unifiedWriter.CreateMetricChannel(topic, m.metadata)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create metrics channel: %w", err)
+	}
+
+	m.channels[topic] = channelID
 	return channelID, nil
 }
 
-// Close is a no-op since the unified writer is managed at the Pulse level
+// Close writes the summaries of open histogram windows.
+// The unified writer itself is managed at the Pulse level.
 func (m *MetricMcapWriter) Close() error {
-	return nil
+	if m.summaries == nil || m.unifiedWriter.IsClosed() {
+		return nil
+	}
+	return m.writeSummaries(m.summaries.flush())
 }
 
 // IsClosed returns whether the writer is closed
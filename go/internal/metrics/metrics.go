@@ -2,13 +2,18 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
-	"strings"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/internal/tags"
 	"github.com/machanirobotics/pulse/go/internal/telemetry"
 	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -17,15 +22,29 @@ type Metrics struct {
 	otelMetrics *telemetry.Metrics
 	mcapWriter  *MetricMcapWriter
 	ctx         context.Context
-	registered  map[string]bool // Track registered metrics
+	instruments *instrumentRegistry  // Metric name -> type, shared with derived instances to catch conflicts
+	cardinality *cardinalityGuard    // Limits attribute sets per metric (nil if unlimited)
+	defaults    []attribute.KeyValue // Attributes added to every metric (from DefaultAttributes)
+	contextKeys []string             // Span attribute/baggage keys copied from ctx (from ContextAttributes)
+	fast        *fastCache           // Instruments and options of the AddInt64 fast path
+	totals      *counterTotals       // Previous values of ;mode=total counters
+	gauges      *observedGauges      // Callbacks registered with RegisterGauge, stopped by Close
+	warnEmpty   bool                 // Warn once per struct type without metric tags (WarnUntagged)
 }
 
 // NewMetrics creates a new Metrics instance
-func NewMetrics(serviceOpts options.ServiceOptions, unifiedWriter *foxglove.UnifiedMcapWriter, otelMetrics *telemetry.Metrics) *Metrics {
+func NewMetrics(serviceOpts options.ServiceOptions, opts options.MetricsTelemetryOptions, unifiedWriter *foxglove.UnifiedMcapWriter, otelMetrics *telemetry.Metrics) *Metrics {
 	m := &Metrics{
 		otelMetrics: otelMetrics,
 		ctx:         context.Background(),
-		registered:  make(map[string]bool),
+		instruments: newInstrumentRegistry(),
+		cardinality: newCardinalityGuard(opts.CardinalityLimit),
+		defaults:    defaultLabels(opts.DefaultAttributes),
+		contextKeys: opts.ContextAttributes,
+		fast:        newFastCache(),
+		totals:      newCounterTotals(),
+		gauges:      newObservedGauges(opts.ExportIntervalSeconds),
+		warnEmpty:   opts.WarnUntagged,
 	}
 
 	// Initialize MCAP writer if unified writer is provided
@@ -43,9 +62,74 @@ func NewMetrics(serviceOpts options.ServiceOptions, unifiedWriter *foxglove.Unif
 	return m
 }
 
+// WithContext returns a new Metrics that records with the specified context
+// (e.g., so exemplars link measurements to the active span)
+func (m *Metrics) WithContext(ctx context.Context) *Metrics {
+	return &Metrics{
+		otelMetrics: m.otelMetrics,
+		mcapWriter:  m.mcapWriter,
+		ctx:         ctx,
+		instruments: m.instruments,
+		cardinality: m.cardinality,
+		defaults:    m.defaults,
+		contextKeys: m.contextKeys,
+		fast:        m.fast,
+		totals:      m.totals,
+		gauges:      m.gauges,
+		warnEmpty:   m.warnEmpty,
+	}
+}
+
 // Record records a metric value from a struct with tags
-// Tag format: `pulse:"metric:type:name"` where type is counter, histogram, gauge
-func (m *Metrics) Record(v any, attrs ...metric.AddOption) error {
+// Tag format: `pulse:"metric:type:name"` where type is counter, histogram, gauge.
+// Options such as metric.WithAttributes apply to every instrument type, including histograms.
+// String and bool fields tagged `pulse:"attribute:key"` are attached as attributes (dimensions)
+// to every metric recorded from the struct.
+func (m *Metrics) Record(v any, attrs ...metric.MeasurementOption) error {
+	return m.RecordAt(m.now(), v, attrs...)
+}
+
+// RecordCtx records metric values from a struct with tags using ctx, like WithContext(ctx).Record.
+// The keys in MetricsTelemetryOptions.ContextAttributes are copied from the span in ctx (or its
+// baggage) as dimensions, so metrics share dimensions with the trace they were recorded in.
+func (m *Metrics) RecordCtx(ctx context.Context, v any, attrs ...metric.MeasurementOption) error {
+	return m.WithContext(ctx).Record(v, attrs...)
+}
+
+// AddCounter adds value to the named counter, with the default attributes and attrs as dimensions.
+// It is the programmatic equivalent of a `pulse:"metric:counter:name"` field passed to Record.
+func (m *Metrics) AddCounter(name string, value float64, attrs ...attribute.KeyValue) error {
+	rec := recording{
+		timestamp: m.now(),
+		labels:    append(m.baseLabels(), attrs...),
+	}
+	if err := m.instruments.register(name, tags.MetricCounter, "AddCounter"); err != nil {
+		return err
+	}
+	return m.recordMetric(tags.Tag{Kind: tags.KindMetric, MetricType: tags.MetricCounter, Name: name}, reflect.ValueOf(value), rec)
+}
+
+// baseLabels returns the default attributes followed by the context attributes, which
+// per-call and struct dimensions appended after them override
+func (m *Metrics) baseLabels() []attribute.KeyValue {
+	labels := append([]attribute.KeyValue(nil), m.defaults...)
+	return append(labels, contextLabels(m.ctx, m.contextKeys)...)
+}
+
+// now returns the current time of the MCAP writer's clock, or the system time without MCAP
+func (m *Metrics) now() time.Time {
+	if m.mcapWriter != nil {
+		return m.mcapWriter.unifiedWriter.Now()
+	}
+	return time.Now()
+}
+
+// RecordAt records metric values from a struct with tags using an explicit timestamp.
+// This is intended for backfilling historical data (e.g., replaying recorded events).
+//
+// Note: OpenTelemetry synchronous instruments cannot be backdated, so the OTLP export
+// still uses the time of the call. Only the MCAP record uses the provided timestamp.
+func (m *Metrics) RecordAt(timestamp time.Time, v any, attrs ...metric.MeasurementOption) error {
 	if v == nil {
 		return nil
 	}
@@ -62,13 +146,175 @@ func (m *Metrics) Record(v any, attrs ...metric.AddOption) error {
 		return fmt.Errorf("Record requires a struct, got %T", v)
 	}
 
-	return m.extractAndRecordMetrics(rv, attrs...)
+	return m.extractAndRecordMetrics(rv, timestamp, nil, attrs...)
+}
+
+// RecordAndReturn records metric values from a struct with tags like Record and returns the
+// recorded metric names and values, e.g. to set them as span attributes without reading the
+// struct twice:
+//
+//	values, err := p.Metrics.RecordAndReturn(stats)
+//	for name, value := range values {
+//	    span.SetAttribute(name, value)
+//	}
+//
+// On error, the metrics recorded before it are returned with the error.
+func (m *Metrics) RecordAndReturn(v any, attrs ...metric.MeasurementOption) (map[string]float64, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Record requires a struct, got %T", v)
+	}
+
+	recorded := make(map[string]float64)
+	err := m.extractAndRecordMetrics(rv, m.now(), recorded, attrs...)
+	return recorded, err
+}
+
+// Validate checks the metric tags of a struct without recording anything.
+// It reports malformed tags (including those of embedded structs), unknown metric types, invalid metric
+// names, non-numeric metric fields and metric names declared with another type (in this struct or one
+// recorded or validated before, see ErrMetricTypeConflict), returning all problems at once.
+// Returns nil if the struct is valid.
+// Validating every metric struct at startup catches type conflicts before any data is recorded.
+func (m *Metrics) Validate(v any) []error {
+	if v == nil {
+		return nil
+	}
+
+	rt := reflect.TypeOf(v)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt.Kind() != reflect.Struct {
+		return []error{fmt.Errorf("Validate requires a struct, got %T", v)}
+	}
+
+	var errs []error
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Attributes of embedded structs are extracted by logging and tracing, so their tags are checked too
+		if embedded, ok := tags.EmbeddedType(field); ok {
+			errs = append(errs, tags.ValidateType(embedded)...)
+			continue
+		}
+
+		tag, ok, err := tags.Lookup(field)
+		if err != nil {
+			// Also report tags whose kind did not parse, e.g. `pulse:"counter:x"`
+			errs = append(errs, err)
+			continue
+		}
+		if !ok || tag.Kind != tags.KindMetric {
+			continue
+		}
+
+		if !isNumericKind(field.Type.Kind()) && !(tag.Parse && field.Type.Kind() == reflect.String) {
+			errs = append(errs, fmt.Errorf("field %s: %s requires numeric value, got %v", field.Name, tag.MetricType, field.Type.Kind()))
+		}
+	}
+
+	errs = append(errs, m.instruments.registerStruct(rt)...)
+	return errs
+}
+
+// isNumericKind reports whether a field kind can be recorded as a metric value
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// recording holds the per-call state shared by every metric extracted from one struct
+type recording struct {
+	timestamp time.Time                  // Timestamp used for the MCAP record
+	labels    []attribute.KeyValue       // Dimensions from `pulse:"attribute:key"` string/bool fields
+	attrs     []metric.MeasurementOption // Caller-provided options (e.g., metric.WithAttributes)
+	recorded  map[string]float64         // Collects the recorded name -> value pairs (RecordAndReturn), if not nil
+}
+
+// collect remembers a recorded value for RecordAndReturn
+func (r recording) collect(name string, value float64) {
+	if r.recorded != nil {
+		r.recorded[name] = value
+	}
+}
+
+// addOptions returns the caller-provided options plus the struct dimensions for Add-style instruments
+func (r recording) addOptions() []metric.AddOption {
+	opts := make([]metric.AddOption, 0, len(r.attrs)+1)
+	for _, opt := range r.attrs {
+		opts = append(opts, opt)
+	}
+	if len(r.labels) > 0 {
+		opts = append(opts, metric.WithAttributes(r.labels...))
+	}
+	return opts
+}
+
+// recordOptions returns the caller-provided options plus the struct dimensions for histograms
+func (r recording) recordOptions() []metric.RecordOption {
+	opts := make([]metric.RecordOption, 0, len(r.attrs)+1)
+	for _, opt := range r.attrs {
+		opts = append(opts, opt)
+	}
+	if len(r.labels) > 0 {
+		opts = append(opts, metric.WithAttributes(r.labels...))
+	}
+	return opts
 }
 
-// extractAndRecordMetrics extracts metrics from struct tags and records them
-func (m *Metrics) extractAndRecordMetrics(rv reflect.Value, attrs ...metric.AddOption) error {
+// labelMap converts the struct dimensions to a map for MCAP
+func (r recording) labelMap() map[string]string {
+	if len(r.labels) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(r.labels))
+	for _, kv := range r.labels {
+		result[string(kv.Key)] = kv.Value.Emit()
+	}
+	return result
+}
+
+// extractAndRecordMetrics extracts metrics from struct tags and records them.
+// If recorded is not nil, the recorded values are added to it by metric name.
+func (m *Metrics) extractAndRecordMetrics(rv reflect.Value, timestamp time.Time, recorded map[string]float64, attrs ...metric.MeasurementOption) error {
 	rt := rv.Type()
 
+	// Refuse the whole struct if a metric name conflicts with an earlier declaration,
+	// rather than recording some fields and failing on others
+	if errs := m.instruments.registerStruct(rt); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	rec := recording{
+		timestamp: timestamp,
+		labels:    append(m.baseLabels(), extractLabels(rv)...),
+		attrs:     attrs,
+		recorded:  recorded,
+	}
+
+	metricFields := 0
 	for i := 0; i < rv.NumField(); i++ {
 		field := rt.Field(i)
 		fieldValue := rv.Field(i)
@@ -77,45 +323,117 @@ func (m *Metrics) extractAndRecordMetrics(rv reflect.Value, attrs ...metric.AddO
 			continue
 		}
 
-		tag := field.Tag.Get("pulse")
-		if tag == "" || !strings.HasPrefix(tag, "metric:") {
-			continue
-		}
-
 		// Parse tag: "metric:type:name"
-		parts := strings.Split(tag, ":")
-		if len(parts) < 3 {
+		tag, ok, err := tags.Lookup(field)
+		if !ok || tag.Kind != tags.KindMetric {
 			continue
 		}
+		if err != nil {
+			return err
+		}
+		metricFields++
 
-		metricType := parts[1]
-		metricName := parts[2]
+		// Parse numeric strings if the tag has the ;parse modifier
+		if tag.Parse && fieldValue.Kind() == reflect.String {
+			parsed, err := strconv.ParseFloat(fieldValue.String(), 64)
+			if err != nil {
+				return fmt.Errorf("field %s: cannot parse %q as a number: %w", field.Name, fieldValue.String(), err)
+			}
+			fieldValue = reflect.ValueOf(parsed)
+		}
 
 		// Record metric based on type
-		if err := m.recordMetric(metricType, metricName, fieldValue, attrs...); err != nil {
+		if err := m.recordMetric(tag, fieldValue, rec); err != nil {
 			return err
 		}
 	}
 
+	if metricFields == 0 && m.warnEmpty {
+		tags.WarnUntagged("Metrics.Record", tags.KindMetric, rt)
+	}
 	return nil
 }
 
-// recordMetric records a single metric value
-func (m *Metrics) recordMetric(metricType, name string, value reflect.Value, attrs ...metric.AddOption) error {
-	switch metricType {
-	case "counter":
-		return m.recordCounter(name, value, attrs...)
-	case "histogram":
-		return m.recordHistogram(name, value, attrs...)
-	case "gauge":
-		return m.recordGauge(name, value, attrs...)
+// extractLabels collects metric dimensions from string and bool fields tagged with `pulse:"attribute:key"`.
+// Fields of other kinds are ignored to keep metric cardinality bounded.
+func extractLabels(rv reflect.Value) []attribute.KeyValue {
+	rt := rv.Type()
+
+	var labels []attribute.KeyValue
+	for i := 0; i < rv.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok, err := tags.Lookup(field)
+		if !ok || err != nil || tag.Kind != tags.KindAttribute {
+			continue
+		}
+
+		fieldValue := rv.Field(i)
+		switch fieldValue.Kind() {
+		case reflect.String:
+			labels = append(labels, attribute.String(tag.Name, fieldValue.String()))
+		case reflect.Bool:
+			labels = append(labels, attribute.Bool(tag.Name, fieldValue.Bool()))
+		}
+	}
+
+	return labels
+}
+
+// defaultLabels converts configured default attributes to metric dimensions, sorted by key.
+// Values that are not strings, bools or numbers are formatted with fmt.
+func defaultLabels(defaults map[string]interface{}) []attribute.KeyValue {
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labels := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		switch v := defaults[k].(type) {
+		case string:
+			labels = append(labels, attribute.String(k, v))
+		case bool:
+			labels = append(labels, attribute.Bool(k, v))
+		case int:
+			labels = append(labels, attribute.Int(k, v))
+		case int64:
+			labels = append(labels, attribute.Int64(k, v))
+		case float64:
+			labels = append(labels, attribute.Float64(k, v))
+		default:
+			labels = append(labels, attribute.String(k, fmt.Sprint(v)))
+		}
+	}
+	return labels
+}
+
+// recordMetric records a single metric value for a parsed metric tag
+func (m *Metrics) recordMetric(tag tags.Tag, value reflect.Value, rec recording) error {
+	name := tag.Name
+
+	// Collapse new attribute sets into the overflow series once the cardinality limit is reached
+	rec.labels = m.cardinality.apply(name, rec.labels)
+
+	switch tag.MetricType {
+	case tags.MetricCounter:
+		return m.recordCounter(name, tag.Total, value, rec)
+	case tags.MetricHistogram:
+		return m.recordHistogram(name, tag.Buckets, value, rec)
+	case tags.MetricGauge:
+		return m.recordGauge(name, value, rec)
 	default:
-		return fmt.Errorf("unknown metric type: %s", metricType)
+		return fmt.Errorf("unknown metric type: %s", tag.MetricType)
 	}
 }
 
-// recordCounter records a counter metric
-func (m *Metrics) recordCounter(name string, value reflect.Value, attrs ...metric.AddOption) error {
+// recordCounter records a counter metric. For a running total (;mode=total), only the
+// increase since the previous value of the same series is added.
+func (m *Metrics) recordCounter(name string, total bool, value reflect.Value, rec recording) error {
 	var val float64
 	switch value.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -127,22 +445,34 @@ func (m *Metrics) recordCounter(name string, value reflect.Value, attrs ...metri
 	default:
 		return fmt.Errorf("counter requires numeric value, got %v", value.Kind())
 	}
+	if total {
+		var tracked bool
+		if val, tracked = m.totals.delta(name, metric.NewAddConfig(rec.addOptions()).Attributes(), val); !tracked {
+			return nil
+		}
+	}
+	rec.collect(name, val)
 
-	counter, err := m.otelMetrics.FloatCounter(name)
-	if err != nil {
-		return err
+	// Record to OTLP (nil if metrics export is disabled)
+	if m.otelMetrics != nil {
+		counter, err := m.otelMetrics.FloatCounter(name)
+		if err != nil {
+			return err
+		}
+		counter.Add(m.ctx, val, rec.addOptions()...)
 	}
-	counter.Add(m.ctx, val, attrs...)
 
 	// Write to MCAP
 	if m.mcapWriter != nil {
-		return m.mcapWriter.WriteCounter(name, val)
+		return m.mcapWriter.WriteCounter(name, val, rec.labelMap(), rec.timestamp)
 	}
 	return nil
 }
 
-// recordHistogram records a histogram metric
-func (m *Metrics) recordHistogram(name string, value reflect.Value, attrs ...metric.AddOption) error {
+// recordHistogram records a histogram metric.
+// Buckets from the ;buckets modifier apply when the instrument is first created; the
+// SDK keeps the boundaries of the first registration for later calls with the same name.
+func (m *Metrics) recordHistogram(name string, buckets []float64, value reflect.Value, rec recording) error {
 	var val float64
 	switch value.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -154,22 +484,30 @@ func (m *Metrics) recordHistogram(name string, value reflect.Value, attrs ...met
 	default:
 		return fmt.Errorf("histogram requires numeric value, got %v", value.Kind())
 	}
+	rec.collect(name, val)
 
-	hist, err := m.otelMetrics.FloatHistogram(name)
-	if err != nil {
-		return err
+	// Record to OTLP (nil if metrics export is disabled)
+	if m.otelMetrics != nil {
+		var histOpts []metric.Float64HistogramOption
+		if len(buckets) > 0 {
+			histOpts = append(histOpts, metric.WithExplicitBucketBoundaries(buckets...))
+		}
+		hist, err := m.otelMetrics.FloatHistogram(name, histOpts...)
+		if err != nil {
+			return err
+		}
+		hist.Record(m.ctx, val, rec.recordOptions()...)
 	}
-	hist.Record(m.ctx, val)
 
 	// Write to MCAP
 	if m.mcapWriter != nil {
-		return m.mcapWriter.WriteHistogram(name, val)
+		return m.mcapWriter.WriteHistogram(name, val, rec.labelMap(), rec.timestamp)
 	}
 	return nil
 }
 
 // recordGauge records a gauge metric (using UpDownCounter for simplicity)
-func (m *Metrics) recordGauge(name string, value reflect.Value, attrs ...metric.AddOption) error {
+func (m *Metrics) recordGauge(name string, value reflect.Value, rec recording) error {
 	var val float64
 	switch value.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -181,25 +519,30 @@ func (m *Metrics) recordGauge(name string, value reflect.Value, attrs ...metric.
 	default:
 		return fmt.Errorf("gauge requires numeric value, got %v", value.Kind())
 	}
+	rec.collect(name, val)
 
 	// Use UpDownCounter as a gauge (can go up and down)
-	gauge, err := m.otelMetrics.FloatUpDownCounter(name)
-	if err != nil {
-		return err
+	// Record to OTLP (nil if metrics export is disabled)
+	if m.otelMetrics != nil {
+		gauge, err := m.otelMetrics.FloatUpDownCounter(name)
+		if err != nil {
+			return err
+		}
+		gauge.Add(m.ctx, val, rec.addOptions()...)
 	}
-	gauge.Add(m.ctx, val, attrs...)
 
 	// Write to MCAP
 	if m.mcapWriter != nil {
-		return m.mcapWriter.WriteGauge(name, val)
+		return m.mcapWriter.WriteGauge(name, val, rec.labelMap(), rec.timestamp)
 	}
 	return nil
 }
 
-// Close closes the metrics system
+// Close closes the metrics system, unregistering the gauges added with RegisterGauge
 func (m *Metrics) Close() error {
+	err := m.gauges.close()
 	if m.mcapWriter != nil {
-		return m.mcapWriter.Close()
+		return errors.Join(err, m.mcapWriter.Close())
 	}
-	return nil
+	return err
 }
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/internal/tags"
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newTestMetrics returns Metrics backed by a meter provider whose metrics are read with reader
+func newTestMetrics(t testing.TB) (*Metrics, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	otelMetrics := telemetry.NewMetrics(provider.Meter("test"))
+	return NewMetrics(options.ServiceOptions{Name: "test"}, options.MetricsTelemetryOptions{}, nil, otelMetrics), reader
+}
+
+// collectMetric returns the metric with the given name from reader
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric %s not recorded", name)
+	return metricdata.Metrics{}
+}
+
+type requestLatency struct {
+	LatencyMs float64 `pulse:"metric:histogram:request.latency_ms"`
+	Route     string  `pulse:"attribute:route"`
+}
+
+func TestRecordHistogramAttributes(t *testing.T) {
+	m, reader := newTestMetrics(t)
+
+	err := m.Record(requestLatency{LatencyMs: 12.5, Route: "/orders"}, metric.WithAttributes(attribute.String("region", "eu")))
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	histogram, ok := collectMetric(t, reader, "request.latency_ms").Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("request.latency_ms is not a float64 histogram")
+	}
+	if len(histogram.DataPoints) != 1 {
+		t.Fatalf("got %d data points, want 1", len(histogram.DataPoints))
+	}
+
+	point := histogram.DataPoints[0]
+	if point.Count != 1 || point.Sum != 12.5 {
+		t.Errorf("got count %d sum %v, want count 1 sum 12.5", point.Count, point.Sum)
+	}
+	for key, want := range map[attribute.Key]string{"route": "/orders", "region": "eu"} {
+		if got, ok := point.Attributes.Value(key); !ok || got.AsString() != want {
+			t.Errorf("attribute %s = %q, want %q", key, got.AsString(), want)
+		}
+	}
+}
+
+type malformedMetrics struct {
+	Requests int64 `pulse:"counter:requests"`          // Kind missing
+	Errors   int64 `pulse:"metrics:counter:errors"`    // Unknown kind
+	Latency  int64 `pulse:"metric:summary:latency_ms"` // Unknown metric type
+	Bytes    int64 `pulse:"metric:counter:bytes"`
+}
+
+func TestValidateReportsMalformedTags(t *testing.T) {
+	m, _ := newTestMetrics(t)
+
+	errs := m.Validate(malformedMetrics{})
+	if len(errs) != 3 {
+		t.Fatalf("Validate() returned %d errors, want 3: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, tags.ErrMalformedTag) {
+			t.Errorf("error %v does not match ErrMalformedTag", err)
+		}
+	}
+}
+
+type BaseRequest struct {
+	Route string `pulse:"attribute:"` // Missing name
+}
+
+type embeddedMetrics struct {
+	BaseRequest
+	Requests int64 `pulse:"metric:counter:embedded.requests"`
+}
+
+func TestValidateEmbeddedTags(t *testing.T) {
+	m, _ := newTestMetrics(t)
+
+	errs := m.Validate(embeddedMetrics{})
+	if len(errs) != 1 || !errors.Is(errs[0], tags.ErrMalformedTag) {
+		t.Errorf("Validate() = %v, want one malformed tag error from BaseRequest", errs)
+	}
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/machanirobotics/pulse/go/internal/tags"
+)
+
+// ErrMetricTypeConflict is returned when a metric name is recorded with a different type
+// than the one it was first registered with (e.g., a counter and a gauge with the same name)
+var ErrMetricTypeConflict = errors.New("conflicting metric type")
+
+// observableGauge is the registry type of RegisterGauge gauges. They are observable instruments, not the
+// up-down counters recorded by `pulse:"metric:gauge:name"` fields, so the two cannot share a name.
+const observableGauge = "observable_gauge"
+
+// instrumentRegistry remembers the type of every metric name and where it was first declared,
+// so conflicting declarations are reported instead of producing duplicate instruments
+type instrumentRegistry struct {
+	mu          sync.Mutex
+	instruments map[string]instrumentSource // metric name -> first declaration
+	structs     map[reflect.Type]bool       // Struct types whose metric fields registered without conflicts
+}
+
+// instrumentSource describes the first declaration of a metric name
+type instrumentSource struct {
+	metricType string // counter, histogram, gauge or observable_gauge
+	source     string // Declaring field (e.g., "RequestMetrics.Count") or function
+}
+
+// newInstrumentRegistry creates an empty registry
+func newInstrumentRegistry() *instrumentRegistry {
+	return &instrumentRegistry{
+		instruments: make(map[string]instrumentSource),
+		structs:     make(map[reflect.Type]bool),
+	}
+}
+
+// register claims the metric name for the type. Returns ErrMetricTypeConflict, naming both
+// declarations, if the name is already registered with another type.
+func (r *instrumentRegistry) register(name, metricType, source string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.registerLocked(name, metricType, source)
+}
+
+// registerStruct registers the metric fields of a struct type and returns one error per conflict.
+// Malformed tags are skipped (they are reported by Record and Validate). Types that registered
+// cleanly are remembered, so repeated calls for the same type are cheap.
+func (r *instrumentRegistry) registerStruct(rt reflect.Type) []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.structs[rt] {
+		return nil
+	}
+
+	var errs []error
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok, err := tags.Lookup(field)
+		if !ok || err != nil || tag.Kind != tags.KindMetric {
+			continue
+		}
+		if err := r.registerLocked(tag.Name, tag.MetricType, rt.Name()+"."+field.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		r.structs[rt] = true
+	}
+	return errs
+}
+
+// registerLocked implements register. Must be called with r.mu held.
+func (r *instrumentRegistry) registerLocked(name, metricType, source string) error {
+	existing, ok := r.instruments[name]
+	if !ok {
+		r.instruments[name] = instrumentSource{metricType: metricType, source: source}
+		return nil
+	}
+	if existing.metricType != metricType {
+		return fmt.Errorf("%w: metric %q is a %s in %s but a %s in %s",
+			ErrMetricTypeConflict, name, existing.metricType, existing.source, metricType, source)
+	}
+	return nil
+}
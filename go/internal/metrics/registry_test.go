@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type queueMetrics struct {
+	Depth float64 `pulse:"metric:gauge:queue.depth"`
+}
+
+type queueCounters struct {
+	Depth int `pulse:"metric:counter:queue.depth"`
+}
+
+func TestRecordTypeConflict(t *testing.T) {
+	m, _ := newTestMetrics(t)
+
+	if err := m.Record(queueMetrics{Depth: 3}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := m.Record(queueCounters{Depth: 1}); !errors.Is(err, ErrMetricTypeConflict) {
+		t.Errorf("Record() of a counter with a gauge's name error = %v, want ErrMetricTypeConflict", err)
+	}
+}
+
+func TestRegisterGaugeConflictsWithGaugeField(t *testing.T) {
+	m, _ := newTestMetrics(t)
+	defer m.Close()
+
+	if err := m.Record(queueMetrics{Depth: 3}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	err := m.RegisterGauge("queue.depth", func(context.Context) float64 { return 3 })
+	if !errors.Is(err, ErrMetricTypeConflict) {
+		t.Errorf("RegisterGauge() error = %v, want ErrMetricTypeConflict", err)
+	}
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSummaryInterval is the histogram summary window used when none is configured
+const defaultSummaryInterval = 10 * time.Second
+
+// summaryQuantiles are the percentiles written for each histogram summary window
+var summaryQuantiles = []struct {
+	suffix   string
+	quantile float64
+}{
+	{"p50", 0.50},
+	{"p95", 0.95},
+	{"p99", 0.99},
+}
+
+// histogramSummaries accumulates histogram values per series and produces percentile
+// summaries once per window. Windows are driven by the sample timestamps, so backfilled
+// data (RecordAt) is summarized like live data.
+type histogramSummaries struct {
+	mu       sync.Mutex
+	interval time.Duration
+	windows  map[string]*summaryWindow // series key (name + sorted labels) -> current window
+}
+
+// summaryWindow holds the values of one series observed in the current window
+type summaryWindow struct {
+	name   string
+	labels map[string]string
+	start  time.Time
+	last   time.Time
+	values []float64
+}
+
+// summaryPoint is a single percentile value to write to MCAP
+type summaryPoint struct {
+	name      string
+	value     float64
+	labels    map[string]string
+	timestamp time.Time
+}
+
+// newHistogramSummaries creates a summary accumulator with the given window
+func newHistogramSummaries(interval time.Duration) *histogramSummaries {
+	if interval <= 0 {
+		interval = defaultSummaryInterval
+	}
+	return &histogramSummaries{
+		interval: interval,
+		windows:  make(map[string]*summaryWindow),
+	}
+}
+
+// add records a histogram value and returns the summary of the previous window
+// of the series if the value starts a new one
+func (h *histogramSummaries) add(name string, value float64, labels map[string]string, timestamp time.Time) []summaryPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := seriesKey(name, labels)
+	w, exists := h.windows[key]
+	if !exists {
+		h.windows[key] = &summaryWindow{name: name, labels: labels, start: timestamp, last: timestamp, values: []float64{value}}
+		return nil
+	}
+
+	var points []summaryPoint
+	if timestamp.Sub(w.start) >= h.interval {
+		points = w.summarize()
+		w.start = timestamp
+		w.values = w.values[:0]
+	}
+
+	w.values = append(w.values, value)
+	w.last = timestamp
+	return points
+}
+
+// flush returns the summaries of every open window and clears them
+func (h *histogramSummaries) flush() []summaryPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.windows))
+	for k := range h.windows {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var points []summaryPoint
+	for _, k := range keys {
+		points = append(points, h.windows[k].summarize()...)
+	}
+	h.windows = make(map[string]*summaryWindow)
+	return points
+}
+
+// summarize computes the window percentiles (nearest-rank), stamped with the last sample time
+func (w *summaryWindow) summarize() []summaryPoint {
+	if len(w.values) == 0 {
+		return nil
+	}
+
+	sorted := append([]float64(nil), w.values...)
+	sort.Float64s(sorted)
+
+	points := make([]summaryPoint, 0, len(summaryQuantiles))
+	for _, q := range summaryQuantiles {
+		rank := int(math.Ceil(q.quantile*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		points = append(points, summaryPoint{
+			name:      w.name + "." + q.suffix,
+			value:     sorted[rank],
+			labels:    w.labels,
+			timestamp: w.last,
+		})
+	}
+	return points
+}
+
+// seriesKey identifies a metric series by name and sorted labels
+func seriesKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString("/" + k + "=" + labels[k])
+	}
+	return b.String()
+}
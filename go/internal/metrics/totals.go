@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// totalKey identifies a counter series: the metric name and its attribute set
+type totalKey struct {
+	name  string
+	attrs attribute.Distinct
+}
+
+// maxTotalSeries bounds the series counterTotals remembers. Without a CardinalityLimit the number of
+// series is unbounded, and forgetting a total would add the next snapshot in full, so new series
+// beyond this are dropped instead.
+const maxTotalSeries = 10000
+
+// counterTotals remembers the last running total of every `;mode=total` counter series,
+// shared with derived instances so a snapshot recorded through WithContext is not counted twice
+type counterTotals struct {
+	mu     sync.Mutex
+	last   map[totalKey]float64
+	warned bool // Series limit warning already printed
+}
+
+// newCounterTotals creates an empty tracker
+func newCounterTotals() *counterTotals {
+	return &counterTotals{last: make(map[totalKey]float64)}
+}
+
+// delta stores total as the latest value of the series and returns the increase since the
+// previous one. The first snapshot of a series is added in full. A total lower than the previous
+// one is treated as a reset of the source (e.g., a restarted component) and is also added in full,
+// so the counter never decreases. Returns ok=false for a new series once maxTotalSeries are tracked.
+func (t *counterTotals) delta(name string, attrs attribute.Set, total float64) (float64, bool) {
+	key := totalKey{name: name, attrs: attrs.Equivalent()}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.last[key]
+	if !ok && len(t.last) >= maxTotalSeries {
+		if !t.warned {
+			t.warned = true
+			fmt.Printf("Warning: %d ;mode=total counter series are tracked, new series such as %s are dropped (set CardinalityLimit)\n", maxTotalSeries, name)
+		}
+		return 0, false
+	}
+	t.last[key] = total
+	if !ok || total < prev {
+		return total, true
+	}
+	return total - prev, true
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCounterTotalsDelta(t *testing.T) {
+	totals := newCounterTotals()
+	attrs := attribute.NewSet(attribute.String("model", "small"))
+
+	for _, step := range []struct {
+		total, want float64
+	}{
+		{total: 1200, want: 1200}, // First snapshot is added in full
+		{total: 1500, want: 300},
+		{total: 100, want: 100}, // Reset of the source
+	} {
+		if got, ok := totals.delta("llm.tokens", attrs, step.total); !ok || got != step.want {
+			t.Errorf("delta(%v) = %v, %v, want %v", step.total, got, ok, step.want)
+		}
+	}
+}
+
+func TestCounterTotalsBounded(t *testing.T) {
+	totals := newCounterTotals()
+	for i := 0; i < maxTotalSeries; i++ {
+		totals.delta("llm.tokens", attribute.NewSet(attribute.String("request.id", strconv.Itoa(i))), 1)
+	}
+
+	if _, ok := totals.delta("llm.tokens", attribute.NewSet(attribute.String("request.id", "new")), 1); ok {
+		t.Error("new series tracked beyond maxTotalSeries")
+	}
+	if got, ok := totals.delta("llm.tokens", attribute.NewSet(attribute.String("request.id", "0")), 5); !ok || got != 4 {
+		t.Errorf("tracked series delta = %v, %v, want 4", got, ok)
+	}
+	if len(totals.last) != maxTotalSeries {
+		t.Errorf("tracked %d series, want %d", len(totals.last), maxTotalSeries)
+	}
+}
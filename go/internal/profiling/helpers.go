@@ -8,20 +8,22 @@ import (
 
 // ProfiledFunc wraps a function with profiling and timing
 func (p *Profiler) ProfiledFunc(ctx context.Context, operation string, fn func() error) error {
+	labels := map[string]string{
+		"operation": operation,
+	}
 	if !p.enabled {
-		return fn()
+		return p.timed(ctx, labels, func(context.Context) error { return fn() })
 	}
 
 	start := time.Now()
 	var err error
 
-	p.TagWrapper(ctx, map[string]string{
-		"operation": operation,
-	}, func(ctx context.Context) {
+	p.TagWrapper(ctx, labels, func(ctx context.Context) {
 		err = fn()
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	// Add error tag if function failed
 	if err != nil {
@@ -39,22 +41,26 @@ func (p *Profiler) ProfiledFunc(ctx context.Context, operation string, fn func()
 
 // ProfiledFuncWithTiming wraps a function with profiling and timing, returns duration
 func (p *Profiler) ProfiledFuncWithTiming(ctx context.Context, operation string, fn func() error) (time.Duration, error) {
+	labels := map[string]string{
+		"operation": operation,
+	}
 	if !p.enabled {
 		start := time.Now()
 		err := fn()
-		return time.Since(start), err
+		duration := time.Since(start)
+		p.recordOperation(ctx, labels, duration, err)
+		return duration, err
 	}
 
 	start := time.Now()
 	var err error
 
-	p.TagWrapper(ctx, map[string]string{
-		"operation": operation,
-	}, func(ctx context.Context) {
+	p.TagWrapper(ctx, labels, func(ctx context.Context) {
 		err = fn()
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	// Add timing and status tags
 	status := "success"
@@ -92,22 +98,24 @@ func (p *Profiler) ProfileSection(ctx context.Context, section string, tags map[
 
 // ProfileDatabaseQuery profiles database query operations
 func (p *Profiler) ProfileDatabaseQuery(ctx context.Context, queryType string, table string, fn func(context.Context) error) error {
+	labels := map[string]string{
+		"operation":  "database_query",
+		"query_type": queryType,
+		"table":      table,
+	}
 	if !p.enabled {
-		return fn(ctx)
+		return p.timed(ctx, labels, fn)
 	}
 
 	var err error
 	start := time.Now()
 
-	p.TagWrapper(ctx, map[string]string{
-		"operation":  "database_query",
-		"query_type": queryType,
-		"table":      table,
-	}, func(ctx context.Context) {
+	p.TagWrapper(ctx, labels, func(ctx context.Context) {
 		err = fn(ctx)
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	status := "success"
 	if err != nil {
@@ -127,9 +135,14 @@ func (p *Profiler) ProfileDatabaseQuery(ctx context.Context, queryType string, t
 }
 
 // ProfileCacheOperation profiles cache operations
+// The cache key is only a profiling tag, it is left out of the recorded metric (high cardinality).
 func (p *Profiler) ProfileCacheOperation(ctx context.Context, operation string, key string, fn func(context.Context) error) error {
+	labels := map[string]string{
+		"operation":       "cache_operation",
+		"cache_operation": operation,
+	}
 	if !p.enabled {
-		return fn(ctx)
+		return p.timed(ctx, labels, fn)
 	}
 
 	var err error
@@ -144,6 +157,7 @@ func (p *Profiler) ProfileCacheOperation(ctx context.Context, operation string,
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	status := "success"
 	if err != nil {
@@ -163,8 +177,12 @@ func (p *Profiler) ProfileCacheOperation(ctx context.Context, operation string,
 
 // ProfileHTTPRequest profiles HTTP request handling
 func (p *Profiler) ProfileHTTPRequest(ctx context.Context, method string, path string, fn func(context.Context) error) error {
+	labels := map[string]string{
+		"operation": "http_request",
+		"method":    method,
+	}
 	if !p.enabled {
-		return fn(ctx)
+		return p.timed(ctx, labels, fn)
 	}
 
 	var err error
@@ -179,6 +197,7 @@ func (p *Profiler) ProfileHTTPRequest(ctx context.Context, method string, path s
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	status := "success"
 	statusCode := 200
@@ -202,8 +221,12 @@ func (p *Profiler) ProfileHTTPRequest(ctx context.Context, method string, path s
 
 // ProfileExternalAPI profiles external API calls
 func (p *Profiler) ProfileExternalAPI(ctx context.Context, service string, endpoint string, fn func(context.Context) error) error {
+	labels := map[string]string{
+		"operation": "external_api",
+		"service":   service,
+	}
 	if !p.enabled {
-		return fn(ctx)
+		return p.timed(ctx, labels, fn)
 	}
 
 	var err error
@@ -218,6 +241,7 @@ func (p *Profiler) ProfileExternalAPI(ctx context.Context, service string, endpo
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, err)
 	
 	status := "success"
 	if err != nil {
@@ -238,21 +262,23 @@ func (p *Profiler) ProfileExternalAPI(ctx context.Context, service string, endpo
 
 // ProfileComputation profiles CPU-intensive computations
 func (p *Profiler) ProfileComputation(ctx context.Context, computationType string, fn func(context.Context)) {
+	labels := map[string]string{
+		"operation":        "computation",
+		"computation_type": computationType,
+	}
 	if !p.enabled {
-		fn(ctx)
+		_ = p.timed(ctx, labels, func(ctx context.Context) error { fn(ctx); return nil })
 		return
 	}
 
 	start := time.Now()
 
-	p.TagWrapper(ctx, map[string]string{
-		"operation":        "computation",
-		"computation_type": computationType,
-	}, func(ctx context.Context) {
+	p.TagWrapper(ctx, labels, func(ctx context.Context) {
 		fn(ctx)
 	})
 
 	duration := time.Since(start)
+	p.recordOperation(ctx, labels, duration, nil)
 
 	// Add timing information
 	p.TagWrapper(ctx, map[string]string{
@@ -263,12 +289,19 @@ func (p *Profiler) ProfileComputation(ctx context.Context, computationType strin
 }
 
 // ProfileMemoryOperation profiles memory-intensive operations
+// The size is only a profiling tag, it is left out of the recorded metric (high cardinality).
 func (p *Profiler) ProfileMemoryOperation(ctx context.Context, operationType string, sizeBytes int64, fn func(context.Context)) {
+	labels := map[string]string{
+		"operation":      "memory_operation",
+		"operation_type": operationType,
+	}
 	if !p.enabled {
-		fn(ctx)
+		_ = p.timed(ctx, labels, func(ctx context.Context) error { fn(ctx); return nil })
 		return
 	}
 
+	start := time.Now()
+
 	p.TagWrapper(ctx, map[string]string{
 		"operation":      "memory_operation",
 		"operation_type": operationType,
@@ -276,4 +309,6 @@ func (p *Profiler) ProfileMemoryOperation(ctx context.Context, operationType str
 	}, func(ctx context.Context) {
 		fn(ctx)
 	})
+
+	p.recordOperation(ctx, labels, time.Since(start), nil)
 }
@@ -0,0 +1,61 @@
+package profiling
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// operationMetric is recorded by the profiling helpers when ProfilingOptions.RecordMetrics is set
+type operationMetric struct {
+	Duration  float64 `pulse:"metric:histogram:profiling.operation.duration_ms"`
+	Count     int     `pulse:"metric:counter:profiling.operation.total"`
+	Operation string  `pulse:"attribute:operation"`
+	Status    string  `pulse:"attribute:status"`
+}
+
+// recordOperation records the latency and outcome of a profiled operation. labels are the helper's
+// profiling tags (operation included); they become metric attributes next to status.
+// No-op unless ProfilingOptions.RecordMetrics is set.
+func (p *Profiler) recordOperation(ctx context.Context, labels map[string]string, duration time.Duration, err error) {
+	if p == nil || p.metrics == nil {
+		return
+	}
+
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+
+	// Sort keys so the attribute order is stable
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if k != "operation" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, labels[k]))
+	}
+
+	_ = p.metrics.WithContext(ctx).Record(operationMetric{ // Ignore metric errors, the operation already ran
+		Duration:  float64(duration.Microseconds()) / 1000,
+		Count:     1,
+		Operation: labels["operation"],
+		Status:    status,
+	}, metric.WithAttributes(attrs...))
+}
+
+// timed runs fn without profiling and records it with recordOperation
+func (p *Profiler) timed(ctx context.Context, labels map[string]string, fn func(context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	p.recordOperation(ctx, labels, time.Since(start), err)
+	return err
+}
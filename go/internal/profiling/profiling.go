@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"runtime/pprof"
+	"time"
 
 	"github.com/grafana/pyroscope-go"
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/internal/metrics"
 	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Profiler wraps the Pyroscope profiler for continuous profiling
@@ -16,13 +20,24 @@ type Profiler struct {
 	enabled     bool
 	mcapWriter  *foxglove.UnifiedMcapWriter
 	serviceName string
+
+	// Records helper latencies and outcomes (nil unless ProfilingOptions.RecordMetrics is set)
+	metrics *metrics.Metrics
 }
 
 // NewProfiler creates and starts a new Pyroscope profiler instance
 // Returns nil if profiling is disabled
-func NewProfiler(serviceOpts options.ServiceOptions, opts options.ProfilingOptions, unifiedMcap *foxglove.UnifiedMcapWriter) *Profiler {
+// m records the latency and outcome of the profiling helpers if opts.RecordMetrics is set, even with profiling disabled
+func NewProfiler(serviceOpts options.ServiceOptions, opts options.ProfilingOptions, unifiedMcap *foxglove.UnifiedMcapWriter, m *metrics.Metrics) *Profiler {
+	if !opts.RecordMetrics {
+		m = nil
+	}
+
 	if !opts.Enabled {
-		return &Profiler{enabled: false}
+		return &Profiler{enabled: false, metrics: m}
+	}
+	if opts.LowOverhead {
+		opts = lowOverhead(opts)
 	}
 
 	// Set mutex and block profile rates if enabled
@@ -56,6 +71,10 @@ func NewProfiler(serviceOpts options.ServiceOptions, opts options.ProfilingOptio
 		Tags:            tags,
 		ProfileTypes:    buildProfileTypes(opts),
 	}
+	if opts.LowOverhead {
+		config.UploadRate = lowOverheadUploadRate
+		config.DisableGCRuns = true // No forced GC before heap profiles, the main GC cost of profiling
+	}
 
 	// Add authentication if provided
 	if opts.BasicAuthUser != "" {
@@ -79,6 +98,7 @@ func NewProfiler(serviceOpts options.ServiceOptions, opts options.ProfilingOptio
 		enabled:     true,
 		mcapWriter:  unifiedMcap,
 		serviceName: serviceOpts.Name,
+		metrics:     m,
 	}
 
 	// TODO: Implement profiling data export to MCAP
@@ -121,6 +141,18 @@ func (p *Profiler) TagWrapper(ctx context.Context, labels map[string]string, fn
 	pyroscope.TagWrapper(ctx, pyroscope.Labels(labelPairs...), fn)
 }
 
+// lowOverheadUploadRate is the profile upload interval with ProfilingOptions.LowOverhead (Pyroscope default: 15s)
+const lowOverheadUploadRate = 60 * time.Second
+
+// lowOverhead applies the profile types of the ProfilingOptions.LowOverhead preset:
+// no allocation profiles, goroutine profiles enabled
+func lowOverhead(opts options.ProfilingOptions) options.ProfilingOptions {
+	opts.ProfileAllocObjects = false
+	opts.ProfileAllocSpace = false
+	opts.ProfileGoroutines = true
+	return opts
+}
+
 // buildProfileTypes constructs the list of profile types based on options
 func buildProfileTypes(opts options.ProfilingOptions) []pyroscope.ProfileType {
 	types := []pyroscope.ProfileType{}
@@ -159,3 +191,25 @@ func buildProfileTypes(opts options.ProfilingOptions) []pyroscope.ProfileType {
 
 	return types
 }
+
+// spanContexter is implemented by *tracing.Span and OpenTelemetry spans
+type spanContexter interface {
+	SpanContext() trace.SpanContext
+}
+
+// WithPprofLabels runs fn with trace_id and span_id pprof labels (runtime/pprof.Do), so CPU
+// profiles captured with standard Go tooling (e.g., net/http/pprof) can be correlated with traces.
+// It works without Pyroscope. If span is nil, the span in ctx is used; without a valid span,
+// fn runs without labels.
+func (p *Profiler) WithPprofLabels(ctx context.Context, span spanContexter, fn func(context.Context)) {
+	sc := trace.SpanContextFromContext(ctx)
+	if span != nil {
+		sc = span.SpanContext()
+	}
+	if !sc.IsValid() {
+		fn(ctx)
+		return
+	}
+
+	pprof.Do(ctx, pprof.Labels("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()), fn)
+}
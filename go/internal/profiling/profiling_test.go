@@ -0,0 +1,160 @@
+package profiling
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/grafana/pyroscope-go"
+	"github.com/machanirobotics/pulse/go/internal/metrics"
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestProfiler returns a profiler with continuous profiling disabled whose helper metrics are read with reader
+func newTestProfiler(t *testing.T) (*Profiler, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	serviceOpts := options.ServiceOptions{Name: "test"}
+	m := metrics.NewMetrics(serviceOpts, options.MetricsTelemetryOptions{}, nil, telemetry.NewMetrics(provider.Meter("test")))
+	return NewProfiler(serviceOpts, options.ProfilingOptions{RecordMetrics: true}, nil, m), reader
+}
+
+// operationAttributes returns the attribute sets of the profiling.operation.total data points
+func operationAttributes(t *testing.T, reader *sdkmetric.ManualReader) []attribute.Set {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[float64]); ok && m.Name == "profiling.operation.total" {
+				sets := make([]attribute.Set, 0, len(sum.DataPoints))
+				for _, point := range sum.DataPoints {
+					sets = append(sets, point.Attributes)
+				}
+				return sets
+			}
+		}
+	}
+	t.Fatal("profiling.operation.total not recorded")
+	return nil
+}
+
+func TestHelperMetricAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    func(p *Profiler) error
+		want    map[attribute.Key]string
+		notWant []attribute.Key
+	}{
+		{
+			name: "http request",
+			call: func(p *Profiler) error {
+				return p.ProfileHTTPRequest(context.Background(), "GET", "/orders/42", func(context.Context) error { return nil })
+			},
+			want:    map[attribute.Key]string{"operation": "http_request", "method": "GET", "status": "success"},
+			notWant: []attribute.Key{"path"},
+		},
+		{
+			name: "external api",
+			call: func(p *Profiler) error {
+				return p.ProfileExternalAPI(context.Background(), "payments", "/charges/ch_123", func(context.Context) error { return errors.New("timeout") })
+			},
+			want:    map[attribute.Key]string{"operation": "external_api", "service": "payments", "status": "error"},
+			notWant: []attribute.Key{"endpoint"},
+		},
+		{
+			name: "cache",
+			call: func(p *Profiler) error {
+				return p.ProfileCacheOperation(context.Background(), "get", "user:42", func(context.Context) error { return nil })
+			},
+			want:    map[attribute.Key]string{"operation": "cache_operation", "cache_operation": "get"},
+			notWant: []attribute.Key{"cache_key"},
+		},
+		{
+			name: "database",
+			call: func(p *Profiler) error {
+				return p.ProfileDatabaseQuery(context.Background(), "select", "users", func(context.Context) error { return nil })
+			},
+			want: map[attribute.Key]string{"operation": "database_query", "query_type": "select", "table": "users"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, reader := newTestProfiler(t)
+			_ = tt.call(p)
+
+			sets := operationAttributes(t, reader)
+			if len(sets) != 1 {
+				t.Fatalf("got %d series, want 1", len(sets))
+			}
+			for key, want := range tt.want {
+				if got, ok := sets[0].Value(key); !ok || got.AsString() != want {
+					t.Errorf("attribute %s = %q, want %q", key, got.AsString(), want)
+				}
+			}
+			for _, key := range tt.notWant {
+				if sets[0].HasValue(key) {
+					t.Errorf("attribute %s recorded, it is a profiling-only tag", key)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpersWithoutMetrics(t *testing.T) {
+	p := NewProfiler(options.ServiceOptions{Name: "test"}, options.ProfilingOptions{}, nil, nil)
+
+	want := errors.New("failed")
+	if err := p.ProfileHTTPRequest(context.Background(), "GET", "/", func(context.Context) error { return want }); err != want {
+		t.Errorf("ProfileHTTPRequest() error = %v, want the error of fn", err)
+	}
+}
+
+func TestBuildProfileTypes(t *testing.T) {
+	opts := options.ProfilingOptions{ProfileCPU: true, ProfileAllocObjects: true, ProfileAllocSpace: true, ProfileInuseSpace: true}
+
+	got := buildProfileTypes(lowOverhead(opts))
+	want := []pyroscope.ProfileType{pyroscope.ProfileCPU, pyroscope.ProfileInuseSpace, pyroscope.ProfileGoroutines}
+	if len(got) != len(want) {
+		t.Fatalf("buildProfileTypes(lowOverhead()) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("profile type %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWithPprofLabels(t *testing.T) {
+	p := NewProfiler(options.ServiceOptions{Name: "test"}, options.ProfilingOptions{}, nil, nil)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+
+	var traceID, spanID string
+	p.WithPprofLabels(trace.ContextWithSpanContext(context.Background(), sc), nil, func(ctx context.Context) {
+		traceID, _ = pprof.Label(ctx, "trace_id")
+		spanID, _ = pprof.Label(ctx, "span_id")
+	})
+	if traceID != sc.TraceID().String() || spanID != sc.SpanID().String() {
+		t.Errorf("pprof labels = %q, %q, want %s, %s", traceID, spanID, sc.TraceID(), sc.SpanID())
+	}
+
+	p.WithPprofLabels(context.Background(), nil, func(ctx context.Context) {
+		if _, ok := pprof.Label(ctx, "trace_id"); ok {
+			t.Error("trace_id label set without a span")
+		}
+	})
+}
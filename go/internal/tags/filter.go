@@ -0,0 +1,50 @@
+package tags
+
+import (
+	"path"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+// Filter decides which attribute keys may be emitted based on allow/deny glob patterns
+type Filter struct {
+	allowed []string
+	denied  []string
+}
+
+// NewFilter creates a Filter from the given options.
+// Returns nil if no patterns are configured; a nil Filter allows every key.
+func NewFilter(opts options.AttributeFilterOptions) *Filter {
+	if len(opts.Allowed) == 0 && len(opts.Denied) == 0 {
+		return nil
+	}
+	return &Filter{
+		allowed: opts.Allowed,
+		denied:  opts.Denied,
+	}
+}
+
+// Allow reports whether the attribute key may be emitted. Denied patterns win over allowed ones.
+func (f *Filter) Allow(key string) bool {
+	if f == nil {
+		return true
+	}
+
+	if matchAny(f.denied, key) {
+		return false
+	}
+	if len(f.allowed) == 0 {
+		return true
+	}
+	return matchAny(f.allowed, key)
+}
+
+// matchAny reports whether key matches any of the glob patterns (malformed patterns never match)
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
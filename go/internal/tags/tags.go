@@ -0,0 +1,275 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Name is the struct tag key used by pulse
+const Name = "pulse"
+
+// Tag kinds supported by the pulse struct tag grammar
+const (
+	KindAttribute = "attribute" // `pulse:"attribute:key_name"` - log attribute
+	KindTrace     = "trace"     // `pulse:"trace:attribute.name"` - span attribute
+	KindMetric    = "metric"    // `pulse:"metric:type:name"` - metric instrument
+)
+
+// Metric types supported by `pulse:"metric:type:name"` tags
+const (
+	MetricCounter   = "counter"
+	MetricHistogram = "histogram"
+	MetricGauge     = "gauge"
+)
+
+// Modifiers appended to metric tags with ';' (e.g., `pulse:"metric:counter:bytes;parse"`)
+const (
+	ModifierParse   = "parse"   // Parse string fields as numbers (strconv.ParseFloat)
+	ModifierBuckets = "buckets" // Histogram bucket boundaries (e.g., `pulse:"metric:histogram:latency_ms;buckets=5,10,50,100"`)
+	ModifierMode    = "mode"    // Counter recording mode (e.g., `pulse:"metric:counter:tokens;mode=total"`)
+)
+
+// Counter recording modes of the ;mode modifier
+const (
+	ModeDelta = "delta" // The field holds the increment since the last Record (default)
+	ModeTotal = "total" // The field holds a running total; only the difference to the previous value is added
+)
+
+// Errors returned (wrapped) for malformed pulse struct tags. Every tag error matches ErrMalformedTag with
+// errors.Is; unknown metric types and invalid metric names also match the more specific error.
+var (
+	ErrMalformedTag      = errors.New("malformed pulse tag")
+	ErrUnknownMetricType = errors.New("unknown metric type")
+	ErrInvalidMetricName = errors.New("invalid metric name")
+)
+
+// FieldError is a tag error of a struct field, returned by Lookup
+type FieldError struct {
+	Field string // Struct field name
+	Err   error  // Tag error, matching ErrMalformedTag
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// validMetricName matches OpenTelemetry instrument names: a letter followed by up to 254
+// letters, digits, '_', '.', '-' or '/'
+var validMetricName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]{0,254}$`)
+
+// Tag is a parsed pulse struct tag
+type Tag struct {
+	Kind       string    // Tag kind (attribute, trace, metric)
+	Name       string    // Attribute or metric name
+	MetricType string    // Metric type (counter, histogram, gauge), only set for metric tags
+	Parse      bool      // Parse string values as numbers (";parse" modifier), only set for metric tags
+	Buckets    []float64 // Explicit histogram bucket boundaries (";buckets=..." modifier), only set for histogram tags
+	Total      bool      // The value is a running total (";mode=total" modifier), only set for counter tags
+}
+
+// Parse parses a pulse struct tag value.
+// Supported formats are "attribute:key_name", "trace:attribute.name" and "metric:type:name",
+// where metric tags may end with ';'-separated modifiers (e.g., "metric:counter:bytes;parse" or
+// "metric:histogram:latency_ms;buckets=5,10,50,100" or "metric:counter:tokens;mode=total").
+// On error, the returned Tag still carries the Kind if it was recognized.
+func Parse(tag string) (Tag, error) {
+	kind, rest, found := strings.Cut(tag, ":")
+	if !found {
+		return Tag{}, fmt.Errorf("%w %q: expected kind:name", ErrMalformedTag, tag)
+	}
+
+	switch kind {
+	case KindAttribute, KindTrace:
+		if rest == "" {
+			return Tag{Kind: kind}, fmt.Errorf("%w %q: missing name", ErrMalformedTag, tag)
+		}
+		return Tag{Kind: kind, Name: rest}, nil
+
+	case KindMetric:
+		rest, modifiers, _ := strings.Cut(rest, ";")
+		metricType, name, found := strings.Cut(rest, ":")
+		if !found || name == "" {
+			return Tag{Kind: kind}, fmt.Errorf("%w %q: expected metric:type:name", ErrMalformedTag, tag)
+		}
+		switch metricType {
+		case MetricCounter, MetricHistogram, MetricGauge:
+		default:
+			return Tag{Kind: kind}, fmt.Errorf("%w %q: %w %q", ErrMalformedTag, tag, ErrUnknownMetricType, metricType)
+		}
+		if !validMetricName.MatchString(name) {
+			return Tag{Kind: kind}, fmt.Errorf("%w %q: %w %q", ErrMalformedTag, tag, ErrInvalidMetricName, name)
+		}
+		parsed := Tag{Kind: kind, Name: name, MetricType: metricType}
+		if modifiers != "" {
+			for _, modifier := range strings.Split(modifiers, ";") {
+				modifier, value, _ := strings.Cut(modifier, "=")
+				switch modifier {
+				case ModifierParse:
+					if value != "" {
+						return Tag{Kind: kind}, fmt.Errorf("%w %q: %s takes no value", ErrMalformedTag, tag, ModifierParse)
+					}
+					parsed.Parse = true
+				case ModifierBuckets:
+					if metricType != MetricHistogram {
+						return Tag{Kind: kind}, fmt.Errorf("%w %q: buckets require a histogram", ErrMalformedTag, tag)
+					}
+					buckets, err := parseBuckets(value)
+					if err != nil {
+						return Tag{Kind: kind}, fmt.Errorf("%w %q: %v", ErrMalformedTag, tag, err)
+					}
+					parsed.Buckets = buckets
+				case ModifierMode:
+					if metricType != MetricCounter {
+						return Tag{Kind: kind}, fmt.Errorf("%w %q: mode requires a counter", ErrMalformedTag, tag)
+					}
+					if value != ModeDelta && value != ModeTotal {
+						return Tag{Kind: kind}, fmt.Errorf("%w %q: unknown mode %q, expected %s or %s", ErrMalformedTag, tag, value, ModeDelta, ModeTotal)
+					}
+					parsed.Total = value == ModeTotal
+				default:
+					return Tag{Kind: kind}, fmt.Errorf("%w %q: unknown modifier %q", ErrMalformedTag, tag, modifier)
+				}
+			}
+		}
+		return parsed, nil
+
+	default:
+		return Tag{}, fmt.Errorf("%w %q: unknown kind %q", ErrMalformedTag, tag, kind)
+	}
+}
+
+// parseBuckets parses comma-separated, strictly increasing histogram bucket boundaries
+func parseBuckets(value string) ([]float64, error) {
+	if value == "" {
+		return nil, errors.New("missing bucket boundaries")
+	}
+
+	parts := strings.Split(value, ",")
+	buckets := make([]float64, len(parts))
+	for i, part := range parts {
+		boundary, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket boundary %q", part)
+		}
+		buckets[i] = boundary
+	}
+
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return nil, errors.New("bucket boundaries must be strictly increasing")
+		}
+	}
+	return buckets, nil
+}
+
+// Lookup parses the pulse tag of a struct field.
+// Returns ok=false if the field has no pulse tag. Errors are *FieldError.
+func Lookup(field reflect.StructField) (tag Tag, ok bool, err error) {
+	value := field.Tag.Get(Name)
+	if value == "" {
+		return Tag{}, false, nil
+	}
+
+	tag, err = Parse(value)
+	if err != nil {
+		return tag, true, &FieldError{Field: field.Name, Err: err}
+	}
+	return tag, true, nil
+}
+
+// Embedded returns the struct held by an exported embedded field without a pulse tag (a struct or
+// non-nil pointer to one), whose tagged fields belong to the outer struct just as Go promotes them.
+// Returns ok=false for any other field.
+func Embedded(field reflect.StructField, value reflect.Value) (reflect.Value, bool) {
+	if !field.Anonymous || !field.IsExported() || field.Tag.Get(Name) != "" {
+		return reflect.Value{}, false
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return value, true
+}
+
+// EmbeddedType returns the struct type of an exported embedded field without a pulse tag (a struct or
+// pointer to one). It is the type-level counterpart of Embedded, used to validate promoted fields.
+func EmbeddedType(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous || !field.IsExported() || field.Tag.Get(Name) != "" {
+		return nil, false
+	}
+	rt := field.Type
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return rt, true
+}
+
+// ValidateStruct checks every pulse tag on the exported fields of a struct (or pointer to struct),
+// including the fields of embedded structs, and returns one error per malformed tag.
+// Returns nil if all tags are valid.
+func ValidateStruct(v any) []error {
+	if v == nil {
+		return nil
+	}
+
+	rt := reflect.TypeOf(v)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt.Kind() != reflect.Struct {
+		return []error{fmt.Errorf("ValidateStruct requires a struct, got %T", v)}
+	}
+
+	return ValidateType(rt)
+}
+
+// ValidateType checks the pulse tags of a struct type like ValidateStruct. Errors of fields promoted
+// from an embedded struct are prefixed with the embedding path (e.g. "Request.BaseRequest").
+func ValidateType(rt reflect.Type) []error {
+	return validateType(rt, rt.Name(), map[reflect.Type]bool{})
+}
+
+// validateType implements ValidateType; seen stops the recursion on structs embedding themselves
+// through a pointer
+func validateType(rt reflect.Type, path string, seen map[reflect.Type]bool) []error {
+	if seen[rt] {
+		return nil
+	}
+	seen[rt] = true
+
+	var errs []error
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if embedded, ok := EmbeddedType(field); ok {
+			errs = append(errs, validateType(embedded, path+"."+field.Name, seen)...)
+			continue
+		}
+
+		if _, _, err := Lookup(field); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,142 @@
+package tags
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Tag
+	}{
+		{tag: "attribute:user_id", want: Tag{Kind: KindAttribute, Name: "user_id"}},
+		{tag: "trace:user.id", want: Tag{Kind: KindTrace, Name: "user.id"}},
+		{tag: "trace:db:statement", want: Tag{Kind: KindTrace, Name: "db:statement"}},
+		{tag: "metric:counter:requests", want: Tag{Kind: KindMetric, Name: "requests", MetricType: MetricCounter}},
+		{tag: "metric:gauge:queue.depth", want: Tag{Kind: KindMetric, Name: "queue.depth", MetricType: MetricGauge}},
+		{tag: "metric:counter:bytes;parse", want: Tag{Kind: KindMetric, Name: "bytes", MetricType: MetricCounter, Parse: true}},
+		{tag: "metric:histogram:latency_ms;buckets=5,10, 50", want: Tag{Kind: KindMetric, Name: "latency_ms", MetricType: MetricHistogram, Buckets: []float64{5, 10, 50}}},
+		{tag: "metric:counter:tokens;mode=total", want: Tag{Kind: KindMetric, Name: "tokens", MetricType: MetricCounter, Total: true}},
+		{tag: "metric:counter:tokens;mode=delta;parse", want: Tag{Kind: KindMetric, Name: "tokens", MetricType: MetricCounter, Parse: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := Parse(tt.tag)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		tag      string
+		wantKind string // Kind still returned with the error
+		wantErr  error  // More specific error than ErrMalformedTag, if any
+	}{
+		{tag: "user_id"},
+		{tag: "traces:user.id"},
+		{tag: "counter:requests"},
+		{tag: "attribute:", wantKind: KindAttribute},
+		{tag: "metric:counter", wantKind: KindMetric},
+		{tag: "metric:counter:", wantKind: KindMetric},
+		{tag: "metric:summary:latency", wantKind: KindMetric, wantErr: ErrUnknownMetricType},
+		{tag: "metric:counter:1requests", wantKind: KindMetric, wantErr: ErrInvalidMetricName},
+		{tag: "metric:counter:bad name", wantKind: KindMetric, wantErr: ErrInvalidMetricName},
+		{tag: "metric:counter:bytes;parse=yes", wantKind: KindMetric},
+		{tag: "metric:counter:latency;buckets=5,10", wantKind: KindMetric},
+		{tag: "metric:histogram:latency;buckets=", wantKind: KindMetric},
+		{tag: "metric:histogram:latency;buckets=10,5", wantKind: KindMetric},
+		{tag: "metric:histogram:latency;buckets=5,fast", wantKind: KindMetric},
+		{tag: "metric:gauge:tokens;mode=total", wantKind: KindMetric},
+		{tag: "metric:counter:tokens;mode=sum", wantKind: KindMetric},
+		{tag: "metric:counter:tokens;unit=ms", wantKind: KindMetric},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := Parse(tt.tag)
+			if !errors.Is(err, ErrMalformedTag) {
+				t.Fatalf("Parse() error = %v, want ErrMalformedTag", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, Tag{Kind: tt.wantKind}) {
+				t.Errorf("Parse() = %+v, want only Kind %q", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	filter := NewFilter(options.AttributeFilterOptions{Allowed: []string{"user.*", "order.id"}, Denied: []string{"*.token", "["}})
+
+	for key, want := range map[string]bool{
+		"user.id":    true,
+		"order.id":   true,
+		"user.token": false, // Denied wins
+		"session":    false, // Not allowed
+	} {
+		if got := filter.Allow(key); got != want {
+			t.Errorf("Allow(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	if NewFilter(options.AttributeFilterOptions{}) != nil {
+		t.Error("NewFilter() without patterns is not nil")
+	}
+	if !(*Filter)(nil).Allow("anything") {
+		t.Error("nil Filter denied a key")
+	}
+}
+
+type BaseRequest struct {
+	RequestID string `pulse:"attribute:"` // Missing name
+}
+
+type Meta struct {
+	Tenant string `pulse:"tenant.id"` // Missing kind
+}
+
+type createOrderRequest struct {
+	BaseRequest
+	*Meta
+	OrderID string `pulse:"trace:order.id"`
+}
+
+func TestValidateStructEmbedded(t *testing.T) {
+	for _, v := range []any{createOrderRequest{}, &createOrderRequest{}} {
+		errs := ValidateStruct(v)
+		if len(errs) != 2 {
+			t.Fatalf("ValidateStruct(%T) returned %d errors, want 2: %v", v, len(errs), errs)
+		}
+		for i, path := range []string{"createOrderRequest.BaseRequest: field RequestID", "createOrderRequest.Meta: field Tenant"} {
+			if !errors.Is(errs[i], ErrMalformedTag) || !strings.HasPrefix(errs[i].Error(), path) {
+				t.Errorf("error %d = %v, want a malformed tag error for %s", i, errs[i], path)
+			}
+		}
+	}
+}
+
+// Tree embeds itself through a pointer
+type Tree struct {
+	*Tree
+	Name string `pulse:"attribute:name"`
+}
+
+func TestValidateStructSelfEmbedding(t *testing.T) {
+	if errs := ValidateStruct(Tree{}); len(errs) != 0 {
+		t.Errorf("ValidateStruct() = %v, want no errors", errs)
+	}
+}
@@ -0,0 +1,25 @@
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// untaggedKey identifies a struct type and tag kind reported by WarnUntagged
+type untaggedKey struct {
+	kind string
+	rt   reflect.Type
+}
+
+// warnedUntagged holds the untaggedKeys already reported, so each is reported once per process
+var warnedUntagged sync.Map
+
+// WarnUntagged prints a warning the first time a struct type that yielded nothing of the given tag kind
+// is passed to caller (e.g., "Metrics.Record"), which usually means misspelled or forgotten tags
+func WarnUntagged(caller, kind string, rt reflect.Type) {
+	if _, warned := warnedUntagged.LoadOrStore(untaggedKey{kind: kind, rt: rt}, true); warned {
+		return
+	}
+	fmt.Printf("Warning: %s got %s without %q pulse tags, nothing was recorded from it\n", caller, rt, kind)
+}
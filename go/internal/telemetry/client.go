@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -39,18 +41,43 @@ type Telemetry struct {
 
 	// Shutdown function
 	shutdownFuncs []func(context.Context) error
+
+	// Errors from signals disabled at startup because of OTLPOptions.FailOpen
+	initErrors []error
+
+	// Sampler and span limits for a tracer provider created by RegisterSpanProcessor
+	tracingOpts options.TracingTelemetryOptions
+
+	// Cancels in-progress exports when the Shutdown deadline expires
+	export *exportContext
 }
 
 // New creates a new Telemetry instance with OpenTelemetry SDK configured
 // based on the provided service and telemetry options.
+// If OTLPOptions.FailOpen is set, a signal whose exporter fails to initialize is disabled
+// instead of failing; the errors are available through InitErrors.
 func New(ctx context.Context, serviceOpts options.ServiceOptions, telemetryOpts options.TelemetryOptions) (*Telemetry, error) {
 	t := &Telemetry{
 		serviceName:   serviceOpts.Name,
 		shutdownFuncs: make([]func(context.Context) error, 0),
+		export:        newExportContext(),
+		tracingOpts:   telemetryOpts.Tracing,
+	}
+
+	if telemetryOpts.OTLP.Enabled {
+		for _, endpoint := range []options.OTLPEndpointOptions{telemetryOpts.Tracing.OTLP, telemetryOpts.Metrics.OTLP, telemetryOpts.Logging.OTLP} {
+			if err := validateOTLPEndpoint(signalOTLP(telemetryOpts.OTLP, endpoint)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := validateViews(telemetryOpts.Metrics); err != nil {
+		return nil, err
 	}
 
 	// Create resource with service information
-	res, err := t.createResource(serviceOpts)
+	res, err := t.createResource(ctx, serviceOpts, telemetryOpts.Resource)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
@@ -59,21 +86,43 @@ func New(ctx context.Context, serviceOpts options.ServiceOptions, telemetryOpts
 	// Initialize tracing
 	if telemetryOpts.Tracing.Enabled {
 		if err := t.initTracing(ctx, telemetryOpts); err != nil {
-			return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+			if !telemetryOpts.OTLP.FailOpen {
+				_ = t.Shutdown(ctx)
+				return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+			}
+			t.initErrors = append(t.initErrors, fmt.Errorf("tracing disabled: %w", err))
 		}
 	}
 
 	// Initialize metrics
 	if telemetryOpts.Metrics.Enabled {
 		if err := t.initMetrics(ctx, telemetryOpts); err != nil {
-			return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+			if !telemetryOpts.OTLP.FailOpen {
+				_ = t.Shutdown(ctx) // Stop the tracer provider started above
+				return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+			}
+			t.initErrors = append(t.initErrors, fmt.Errorf("metrics disabled: %w", err))
+		}
+	}
+
+	// Record span durations as metrics once both pipelines are set up
+	if telemetryOpts.Tracing.RecordSpanDurations && t.tracerProvider != nil && t.meterProvider != nil {
+		processor, err := newSpanDurationProcessor(t.meterProvider.Meter(t.serviceName))
+		if err != nil {
+			_ = t.Shutdown(ctx)
+			return nil, err
 		}
+		t.tracerProvider.RegisterSpanProcessor(processor)
 	}
 
 	// Initialize logging
 	if telemetryOpts.Logging.Enabled {
 		if err := t.initLogging(ctx, telemetryOpts); err != nil {
-			return nil, fmt.Errorf("failed to initialize logging: %w", err)
+			if !telemetryOpts.OTLP.FailOpen {
+				_ = t.Shutdown(ctx) // Stop the tracer and meter providers started above
+				return nil, fmt.Errorf("failed to initialize logging: %w", err)
+			}
+			t.initErrors = append(t.initErrors, fmt.Errorf("logging disabled: %w", err))
 		}
 	}
 
@@ -81,20 +130,25 @@ func New(ctx context.Context, serviceOpts options.ServiceOptions, telemetryOpts
 }
 
 // createResource creates an OpenTelemetry resource with service metadata
-func (t *Telemetry) createResource(serviceOpts options.ServiceOptions) (*resource.Resource, error) {
+// and the output of the configured resource detectors
+func (t *Telemetry) createResource(ctx context.Context, serviceOpts options.ServiceOptions, opts options.ResourceOptions) (*resource.Resource, error) {
 	// Create resource with service attributes
 	// Note: We don't specify SchemaURL to avoid conflicts with resource.Default()
-	customResource, err := resource.New(
-		context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceName(serviceOpts.Name),
-			semconv.ServiceVersion(serviceOpts.Version),
-			attribute.String("service.description", serviceOpts.Description),
-			attribute.String("environment", string(serviceOpts.Environment)),
-		),
-	)
-	if err != nil {
-		return nil, err
+	resourceOpts := []resource.Option{resource.WithAttributes(
+		semconv.ServiceName(serviceOpts.Name),
+		semconv.ServiceVersion(serviceOpts.Version),
+		attribute.String("service.description", serviceOpts.Description),
+		attribute.String("environment", string(serviceOpts.Environment)),
+	)}
+	resourceOpts = append(resourceOpts, resourceDetectors(opts)...)
+
+	// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME come last so they override the service options,
+	// as in the other OTel SDKs (OTEL_SERVICE_NAME wins over a service.name in OTEL_RESOURCE_ATTRIBUTES)
+	resourceOpts = append(resourceOpts, resource.WithFromEnv())
+
+	customResource, err := resource.New(ctx, resourceOpts...)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err // Keep a partial resource if only some detectors failed (e.g., no host ID in containers)
 	}
 
 	// Merge with default resource
@@ -104,6 +158,34 @@ func (t *Telemetry) createResource(serviceOpts options.ServiceOptions) (*resourc
 	)
 }
 
+// resourceDetectors returns the resource options for the enabled detectors.
+// Process detection leaves out command args since they may contain secrets.
+func resourceDetectors(opts options.ResourceOptions) []resource.Option {
+	var detectors []resource.Option
+	if opts.Host {
+		// Same host name as LogOptions.IncludeHost, see HostName
+		if host := HostName(); host != "" {
+			detectors = append(detectors, resource.WithAttributes(attribute.String("host.name", host)))
+		}
+		detectors = append(detectors, resource.WithHostID())
+	}
+	if opts.Process {
+		detectors = append(detectors,
+			resource.WithProcessPID(),
+			resource.WithProcessExecutableName(),
+			resource.WithProcessExecutablePath(),
+			resource.WithProcessOwner(),
+			resource.WithProcessRuntimeName(),
+			resource.WithProcessRuntimeVersion(),
+			resource.WithProcessRuntimeDescription(),
+		)
+	}
+	if opts.Container {
+		detectors = append(detectors, resource.WithContainer())
+	}
+	return detectors
+}
+
 // initTracing initializes the OpenTelemetry tracing pipeline
 func (t *Telemetry) initTracing(ctx context.Context, opts options.TelemetryOptions) error {
 	var exporter sdktrace.SpanExporter
@@ -111,11 +193,24 @@ func (t *Telemetry) initTracing(ctx context.Context, opts options.TelemetryOptio
 
 	if opts.OTLP.Enabled {
 		// Use OTLP exporter for production
-		endpoint := fmt.Sprintf("%s:%d", opts.OTLP.Host, opts.OTLP.Port)
-		exporter, err = otlptracegrpc.New(ctx,
+		endpoint, dialOpts := otlpEndpoint(signalOTLP(opts.OTLP, opts.Tracing.OTLP))
+		var compressor string
+		compressor, err = compressorName(opts.OTLP.TraceCompression, opts.OTLP.Compression)
+		if err != nil {
+			return fmt.Errorf("failed to create trace exporter: %w", err)
+		}
+		exporterOpts := []otlptracegrpc.Option{
 			otlptracegrpc.WithEndpoint(endpoint),
 			otlptracegrpc.WithInsecure(), // Use WithTLSCredentials() in production
-		)
+			otlptracegrpc.WithRetry(retryConfig(opts.OTLP.Retry)),
+		}
+		if compressor != "" {
+			exporterOpts = append(exporterOpts, otlptracegrpc.WithCompressor(compressor))
+		}
+		if len(dialOpts) > 0 {
+			exporterOpts = append(exporterOpts, otlptracegrpc.WithDialOption(dialOpts...))
+		}
+		exporter, err = otlptracegrpc.New(ctx, exporterOpts...)
 	} else {
 		// No exporter in development - skip stdout to reduce noise
 		return nil
@@ -125,23 +220,61 @@ func (t *Telemetry) initTracing(ctx context.Context, opts options.TelemetryOptio
 		return fmt.Errorf("failed to create trace exporter: %w", err)
 	}
 
+	// Batch spans for export (or export each span as it ends), behind the tail sampler if enabled
+	var processor sdktrace.SpanProcessor
+	if opts.Tracing.SyncExport {
+		processor = sdktrace.NewSimpleSpanProcessor(&spanExporter{SpanExporter: exporter, export: t.export})
+	} else {
+		processor = sdktrace.NewBatchSpanProcessor(&spanExporter{SpanExporter: exporter, export: t.export})
+	}
+	if opts.Tracing.TailSampling.Enabled {
+		processor = newTailSampler(processor, opts.Tracing.TailSampling)
+	}
+
 	// Create tracer provider
 	t.tracerProvider = sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSpanProcessor(processor),
 		sdktrace.WithResource(t.resource),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(newSampler(opts.Tracing)),
+		sdktrace.WithSpanLimits(newSpanLimits(opts.Tracing.SpanLimits)),
 	)
 
+	t.useTracerProvider()
+	return nil
+}
+
+// useTracerProvider installs t.tracerProvider globally and creates the tracer wrapper
+func (t *Telemetry) useTracerProvider() {
 	// Set global tracer provider
 	otel.SetTracerProvider(t.tracerProvider)
 
+	// Propagate W3C trace context and baggage so traces continue across process boundaries
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
 	// Add shutdown function
 	t.shutdownFuncs = append(t.shutdownFuncs, t.tracerProvider.Shutdown)
 
 	// Create tracer wrapper
 	t.tracer = NewTracer(t.tracerProvider.Tracer(t.serviceName))
+}
 
-	return nil
+// RegisterSpanProcessor adds a processor that sees every span ended after the call
+// (e.g., the MCAP span timeline). If no tracing pipeline was set up because OTLP is disabled,
+// a tracer provider is created just for the processor, with the configured sampler and span limits,
+// so spans are recorded in MCAP-only setups.
+func (t *Telemetry) RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	if t.tracerProvider != nil {
+		t.tracerProvider.RegisterSpanProcessor(processor)
+		return
+	}
+
+	t.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(t.resource),
+		sdktrace.WithSampler(newSampler(t.tracingOpts)),
+		sdktrace.WithSpanLimits(newSpanLimits(t.tracingOpts.SpanLimits)),
+	)
+	t.useTracerProvider()
 }
 
 // initMetrics initializes the OpenTelemetry metrics pipeline
@@ -151,11 +284,24 @@ func (t *Telemetry) initMetrics(ctx context.Context, opts options.TelemetryOptio
 
 	if opts.OTLP.Enabled {
 		// Use OTLP exporter for production
-		endpoint := fmt.Sprintf("%s:%d", opts.OTLP.Host, opts.OTLP.Port)
-		exporter, err = otlpmetricgrpc.New(ctx,
+		endpoint, dialOpts := otlpEndpoint(signalOTLP(opts.OTLP, opts.Metrics.OTLP))
+		var compressor string
+		compressor, err = compressorName(opts.OTLP.MetricCompression, opts.OTLP.Compression)
+		if err != nil {
+			return fmt.Errorf("failed to create metric exporter: %w", err)
+		}
+		exporterOpts := []otlpmetricgrpc.Option{
 			otlpmetricgrpc.WithEndpoint(endpoint),
 			otlpmetricgrpc.WithInsecure(), // Use WithTLSCredentials() in production
-		)
+			otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(retryConfig(opts.OTLP.Retry))),
+		}
+		if compressor != "" {
+			exporterOpts = append(exporterOpts, otlpmetricgrpc.WithCompressor(compressor))
+		}
+		if len(dialOpts) > 0 {
+			exporterOpts = append(exporterOpts, otlpmetricgrpc.WithDialOption(dialOpts...))
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, exporterOpts...)
 	} else {
 		// No exporter in development - skip stdout to reduce noise
 		return nil
@@ -166,12 +312,25 @@ func (t *Telemetry) initMetrics(ctx context.Context, opts options.TelemetryOptio
 	}
 
 	// Create meter provider
-	t.meterProvider = sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+	providerOpts := []sdkmetric.Option{
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(&metricExporter{Exporter: exporter, export: t.export},
 			sdkmetric.WithInterval(time.Duration(opts.Metrics.ExportIntervalSeconds)*time.Second),
 		)),
 		sdkmetric.WithResource(t.resource),
-	)
+	}
+
+	// Apply configured views (e.g., exponential histograms)
+	if views := buildViews(opts.Metrics); len(views) > 0 {
+		providerOpts = append(providerOpts, sdkmetric.WithView(views...))
+	}
+
+	// Limit attribute sets per instrument; extra sets are aggregated into the
+	// otel.metric.overflow=true series by the SDK
+	if opts.Metrics.CardinalityLimit > 0 {
+		providerOpts = append(providerOpts, sdkmetric.WithCardinalityLimit(opts.Metrics.CardinalityLimit))
+	}
+
+	t.meterProvider = sdkmetric.NewMeterProvider(providerOpts...)
 
 	// Set global meter provider
 	otel.SetMeterProvider(t.meterProvider)
@@ -192,15 +351,38 @@ func (t *Telemetry) initLogging(ctx context.Context, opts options.TelemetryOptio
 	// Only add OTLP exporter if enabled (for Loki/remote logging)
 	// Console output is handled by the charmbracelet logger
 	if opts.OTLP.Enabled {
-		endpoint := fmt.Sprintf("%s:%d", opts.OTLP.Host, opts.OTLP.Port)
-		otlpExporter, err := otlploggrpc.New(ctx,
+		endpoint, dialOpts := otlpEndpoint(signalOTLP(opts.OTLP, opts.Logging.OTLP))
+		compressor, err := compressorName(opts.OTLP.LogCompression, opts.OTLP.Compression)
+		if err != nil {
+			return fmt.Errorf("failed to create OTLP log exporter: %w", err)
+		}
+		exporterOpts := []otlploggrpc.Option{
 			otlploggrpc.WithEndpoint(endpoint),
 			otlploggrpc.WithInsecure(), // Use WithTLSCredentials() in production
-		)
+			otlploggrpc.WithRetry(otlploggrpc.RetryConfig(retryConfig(opts.OTLP.Retry))),
+		}
+		if compressor != "" {
+			exporterOpts = append(exporterOpts, otlploggrpc.WithCompressor(compressor))
+		}
+		if len(dialOpts) > 0 {
+			exporterOpts = append(exporterOpts, otlploggrpc.WithDialOption(dialOpts...))
+		}
+		otlpExporter, err := otlploggrpc.New(ctx, exporterOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create OTLP log exporter: %w", err)
 		}
-		processors = append(processors, sdklog.NewBatchProcessor(otlpExporter))
+		processors = append(processors, sdklog.NewBatchProcessor(&logExporter{Exporter: otlpExporter, export: t.export}))
+	}
+
+	// Write records to a JSON lines file if configured (works without a collector)
+	var closeFile func(context.Context) error
+	if opts.Logging.FilePath != "" {
+		fileExporter, closer, err := newLogFileExporter(opts.Logging.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to create log file exporter: %w", err)
+		}
+		processors = append(processors, sdklog.NewBatchProcessor(fileExporter))
+		closeFile = closer
 	}
 
 	// Create logger provider with all processors
@@ -217,6 +399,9 @@ func (t *Telemetry) initLogging(ctx context.Context, opts options.TelemetryOptio
 
 	// Add shutdown function
 	t.shutdownFuncs = append(t.shutdownFuncs, t.loggerProvider.Shutdown)
+	if closeFile != nil {
+		t.shutdownFuncs = append(t.shutdownFuncs, closeFile) // After the provider has flushed the file
+	}
 
 	// Create logger wrapper
 	t.Logger = NewLogger(t.loggerProvider.Logger(t.serviceName), opts.Logging)
@@ -224,6 +409,22 @@ func (t *Telemetry) initLogging(ctx context.Context, opts options.TelemetryOptio
 	return nil
 }
 
+// InitErrors returns the errors of signals that were disabled at startup
+// because their exporter could not be created and OTLPOptions.FailOpen is set
+func (t *Telemetry) InitErrors() []error {
+	return t.initErrors
+}
+
+// ResourceAttributes returns the attributes of the service resource (service name/version,
+// environment, detected host/process/container attributes and OTEL_RESOURCE_ATTRIBUTES) as strings
+func (t *Telemetry) ResourceAttributes() map[string]string {
+	attrs := make(map[string]string, t.resource.Len())
+	for _, kv := range t.resource.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
+
 // GetLogger returns the underlying OpenTelemetry logger
 func (t *Telemetry) GetLogger() log.Logger {
 	if t.loggerProvider != nil {
@@ -242,8 +443,16 @@ func (t *Telemetry) GetTracer() *Tracer {
 	return t.tracer
 }
 
-// Shutdown gracefully shuts down all telemetry providers
-func (t *Telemetry) Shutdown(ctx context.Context) error {
+// ForceFlush exports all pending spans, metrics and logs without shutting down the providers
+func (t *Telemetry) ForceFlush(ctx context.Context) error {
+	if errs := t.forceFlush(ctx); len(errs) > 0 {
+		return fmt.Errorf("flush errors: %v", errs)
+	}
+	return nil
+}
+
+// forceFlush flushes every provider and returns the errors encountered
+func (t *Telemetry) forceFlush(ctx context.Context) []error {
 	var errs []error
 
 	// Force flush tracer provider first to ensure all spans are exported
@@ -267,6 +476,20 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	return errs
+}
+
+// Shutdown gracefully shuts down all telemetry providers.
+// If ctx has a deadline, in-progress exports (including periodic metric exports and batch
+// span/log exports started before Shutdown) are cancelled when it expires, so shutdown does not
+// exceed the caller's grace period even if the SDK export timeouts are longer.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	stop := t.export.cancelOnDone(ctx)
+	defer stop()
+	defer t.export.cancel() // Nothing is exported after shutdown
+
+	errs := t.forceFlush(ctx)
+
 	// Now shutdown all providers
 	for _, fn := range t.shutdownFuncs {
 		if err := fn(ctx); err != nil {
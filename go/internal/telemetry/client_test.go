@@ -0,0 +1,69 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+// failingExporterOptions enables one signal with an OTLP host the gRPC exporters cannot parse,
+// so creating that signal's exporter fails
+func failingExporterOptions(signal string, failOpen bool) options.TelemetryOptions {
+	opts := options.TelemetryOptions{
+		OTLP: options.OTLPOptions{Enabled: true, Host: "bad\x7fhost", Port: 4317, FailOpen: failOpen},
+	}
+	switch signal {
+	case "tracing":
+		opts.Tracing.Enabled = true
+	case "metrics":
+		opts.Metrics.Enabled = true
+		opts.Metrics.ExportIntervalSeconds = 60
+	case "logging":
+		opts.Logging.Enabled = true
+	}
+	return opts
+}
+
+func TestNewExporterError(t *testing.T) {
+	for _, signal := range []string{"tracing", "metrics", "logging"} {
+		t.Run(signal, func(t *testing.T) {
+			tel, err := New(context.Background(), options.ServiceOptions{Name: "test"}, failingExporterOptions(signal, false))
+			if err == nil {
+				_ = tel.Shutdown(context.Background())
+				t.Fatal("New succeeded with an exporter that cannot be created")
+			}
+			if !strings.Contains(err.Error(), "failed to initialize "+signal) {
+				t.Errorf("error = %v, want the %s initialization error", err, signal)
+			}
+		})
+	}
+}
+
+func TestNewExporterErrorFailOpen(t *testing.T) {
+	for _, signal := range []string{"tracing", "metrics", "logging"} {
+		t.Run(signal, func(t *testing.T) {
+			tel, err := New(context.Background(), options.ServiceOptions{Name: "test"}, failingExporterOptions(signal, true))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer tel.Shutdown(context.Background())
+
+			initErrors := tel.InitErrors()
+			if len(initErrors) != 1 || !strings.Contains(initErrors[0].Error(), signal+" disabled") {
+				t.Errorf("InitErrors() = %v, want one %s disabled error", initErrors, signal)
+			}
+			switch signal {
+			case "tracing":
+				if tel.tracerProvider != nil {
+					t.Error("tracer provider created for a disabled signal")
+				}
+			case "metrics":
+				if tel.meterProvider != nil {
+					t.Error("meter provider created for a disabled signal")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/machanirobotics/pulse/go/options"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// compressorName returns the gRPC compressor for a signal, falling back to the shared OTLP compression.
+// An empty name means the exporter sends uncompressed requests.
+func compressorName(signal, fallback options.OTLPCompression) (string, error) {
+	compression := signal
+	if compression == "" {
+		compression = fallback
+	}
+
+	switch compression {
+	case "", options.OTLPCompressionNone:
+		return "", nil
+	case options.OTLPCompressionGzip, options.OTLPCompressionZstd:
+		return string(compression), nil
+	default:
+		return "", fmt.Errorf("unsupported OTLP compression %q", compression)
+	}
+}
+
+// zstdCompressor is a gRPC compressor using zstd, registered as "zstd"
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+// Name returns the compressor name sent in the grpc-encoding header
+func (c *zstdCompressor) Name() string {
+	return string(options.OTLPCompressionZstd)
+}
+
+// Compress returns a writer that compresses to w; closing it returns the encoder to the pool
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if enc, ok := c.encoders.Get().(*zstd.Encoder); ok {
+		enc.Reset(w)
+		return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+	}
+
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+// Decompress returns a reader that decompresses r
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if dec, ok := c.decoders.Get().(*zstd.Decoder); ok {
+		if err := dec.Reset(r); err != nil {
+			return nil, err
+		}
+		return &zstdReader{Decoder: dec, pool: &c.decoders}, nil
+	}
+
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdReader{Decoder: dec, pool: &c.decoders}, nil
+}
+
+// zstdWriter returns its encoder to the pool once the message is compressed
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+// Close flushes the compressed message and releases the encoder
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// zstdReader returns its decoder to the pool once the message is fully read
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+// Read reads decompressed data and releases the decoder at the end of the message
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.Decoder == nil {
+		return 0, io.EOF
+	}
+
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Decoder)
+		r.Decoder = nil
+	}
+	return n, err
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+func TestCompressorName(t *testing.T) {
+	tests := []struct {
+		name     string
+		signal   options.OTLPCompression
+		fallback options.OTLPCompression
+		want     string
+		wantErr  bool
+	}{
+		{name: "unset", want: ""},
+		{name: "none", signal: options.OTLPCompressionNone, want: ""},
+		{name: "gzip", signal: options.OTLPCompressionGzip, want: "gzip"},
+		{name: "zstd", signal: options.OTLPCompressionZstd, want: "zstd"},
+		{name: "fallback", fallback: options.OTLPCompressionGzip, want: "gzip"},
+		{name: "signal wins", signal: options.OTLPCompressionZstd, fallback: options.OTLPCompressionGzip, want: "zstd"},
+		{name: "none overrides fallback", signal: options.OTLPCompressionNone, fallback: options.OTLPCompressionGzip, want: ""},
+		{name: "unknown", signal: "brotli", wantErr: true},
+		{name: "unknown fallback", fallback: "GZIP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compressorName(tt.signal, tt.fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compressorName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compressorName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
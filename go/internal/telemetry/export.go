@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"context"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exportContext ties the internal contexts the SDK uses for periodic and batch exports
+// to application shutdown. The SDK derives those contexts from context.Background with its
+// own export timeout, so without this an in-progress export can outlive the deadline passed to Shutdown.
+type exportContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newExportContext creates an export context that is cancelled by cancelExports
+func newExportContext() *exportContext {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &exportContext{ctx: ctx, cancel: cancel}
+}
+
+// with returns a context that is cancelled when either ctx or the export context is done
+func (e *exportContext) with(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(e.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// cancelOnDone cancels in-progress exports once ctx is done (e.g., the Shutdown deadline expires).
+// The returned function stops waiting on ctx.
+func (e *exportContext) cancelOnDone(ctx context.Context) func() bool {
+	return context.AfterFunc(ctx, e.cancel)
+}
+
+// spanExporter cancels in-progress span exports on shutdown
+type spanExporter struct {
+	sdktrace.SpanExporter
+	export *exportContext
+}
+
+// ExportSpans exports spans with a shutdown-aware context
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	ctx, cancel := e.export.with(ctx)
+	defer cancel()
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
+
+// metricExporter cancels in-progress metric exports on shutdown
+type metricExporter struct {
+	sdkmetric.Exporter
+	export *exportContext
+}
+
+// Export exports metrics with a shutdown-aware context
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	ctx, cancel := e.export.with(ctx)
+	defer cancel()
+	return e.Exporter.Export(ctx, rm)
+}
+
+// logExporter cancels in-progress log exports on shutdown
+type logExporter struct {
+	sdklog.Exporter
+	export *exportContext
+}
+
+// Export exports log records with a shutdown-aware context
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	ctx, cancel := e.export.with(ctx)
+	defer cancel()
+	return e.Exporter.Export(ctx, records)
+}
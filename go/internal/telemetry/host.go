@@ -0,0 +1,21 @@
+package telemetry
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	hostNameOnce sync.Once
+	hostName     string
+)
+
+// HostName returns the host name (the pod name in Kubernetes), read once per process.
+// It is the host.name used by the resource detector, so logs that include it
+// (LogOptions.IncludeHost) match the resource of every signal. Returns "" if it cannot be read.
+func HostName() string {
+	hostNameOnce.Do(func() {
+		hostName, _ = os.Hostname()
+	})
+	return hostName
+}
@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"github.com/machanirobotics/pulse/go/options"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newSpanLimits returns the SDK span limits with the configured (non-zero) overrides applied
+func newSpanLimits(opts options.SpanLimitsOptions) sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits() // SDK defaults, including OTEL_SPAN_* environment variables
+
+	if opts.MaxAttributes > 0 {
+		limits.AttributeCountLimit = opts.MaxAttributes
+	}
+	if opts.MaxEvents > 0 {
+		limits.EventCountLimit = opts.MaxEvents
+	}
+	if opts.MaxLinks > 0 {
+		limits.LinkCountLimit = opts.MaxLinks
+	}
+	if opts.MaxAttributeValueLength > 0 {
+		limits.AttributeValueLengthLimit = opts.MaxAttributeValueLength
+	}
+
+	return limits
+}
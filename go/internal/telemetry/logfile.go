@@ -0,0 +1,35 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+)
+
+// newLogFileExporter creates a log exporter that appends each record as a JSON line to path
+// (the OpenTelemetry SDK stdout format, with resource, scope, attributes and trace context).
+// The returned function closes the file; call it after the logger provider is shut down.
+func newLogFileExporter(path string) (*stdoutlog.Exporter, func(context.Context) error, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	exporter, err := stdoutlog.New(stdoutlog.WithWriter(file))
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	closeFile := func(context.Context) error {
+		return file.Close()
+	}
+	return exporter, closeFile, nil
+}
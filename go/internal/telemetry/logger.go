@@ -77,6 +77,7 @@ func (l *Logger) emit(ctx context.Context, severity log.Severity, msg string, at
 	var record log.Record
 	record.SetBody(log.StringValue(msg))
 	record.SetSeverity(severity)
+	record.SetSeverityText(severity.String())
 	record.AddAttributes(attrs...)
 
 	l.logger.Emit(ctx, record)
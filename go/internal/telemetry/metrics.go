@@ -58,6 +58,12 @@ func (m *Metrics) FloatGauge(name string, opts ...metric.Float64ObservableGaugeO
 	return m.meter.Float64ObservableGauge(name, opts...)
 }
 
+// RegisterCallback registers f to observe the instruments on each collection.
+// Unregister the returned registration to stop the callback.
+func (m *Metrics) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	return m.meter.RegisterCallback(f, instruments...)
+}
+
 // RecordInt64 is a helper to record a single int64 value
 func (m *Metrics) RecordInt64(ctx context.Context, name string, value int64, opts ...metric.Int64CounterOption) error {
 	counter, err := m.Counter(name, opts...)
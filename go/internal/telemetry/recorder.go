@@ -0,0 +1,25 @@
+package telemetry
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// RecordSpans registers an in-memory span recorder that keeps every span started after the call
+// (TracingOptions.RecordSpans). If no tracing pipeline was set up (no exporter, or tracing telemetry
+// disabled), a tracer provider that samples every span is created just for the recorder, so spans
+// can be asserted on in tests without a collector.
+func (t *Telemetry) RecordSpans() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	if t.tracerProvider != nil {
+		t.tracerProvider.RegisterSpanProcessor(recorder)
+		return recorder
+	}
+
+	t.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithResource(t.resource),
+	)
+	t.useTracerProvider()
+	return recorder
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"time"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+)
+
+// Default retry settings, matching the OTLP exporter defaults
+const (
+	defaultRetryInitialInterval = 5 * time.Second
+	defaultRetryMaxInterval     = 30 * time.Second
+	defaultRetryMaxElapsedTime  = time.Minute
+)
+
+// retryConfig returns the exporter retry settings with zero intervals set to the defaults.
+// The trace, metric and log exporters use identical config structs, so the result converts
+// to otlpmetricgrpc.RetryConfig and otlploggrpc.RetryConfig.
+func retryConfig(opts options.OTLPRetryOptions) otlptracegrpc.RetryConfig {
+	cfg := otlptracegrpc.RetryConfig{
+		Enabled:         opts.Enabled,
+		InitialInterval: time.Duration(opts.InitialIntervalSeconds) * time.Second,
+		MaxInterval:     time.Duration(opts.MaxIntervalSeconds) * time.Second,
+		MaxElapsedTime:  time.Duration(opts.MaxElapsedTimeSeconds) * time.Second,
+	}
+
+	if cfg.InitialInterval <= 0 {
+		cfg.InitialInterval = defaultRetryInitialInterval
+	}
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = defaultRetryMaxInterval
+	}
+	if cfg.MaxElapsedTime <= 0 {
+		cfg.MaxElapsedTime = defaultRetryMaxElapsedTime
+	}
+
+	return cfg
+}
@@ -0,0 +1,41 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+)
+
+func TestRetryConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opts options.OTLPRetryOptions
+		want otlptracegrpc.RetryConfig
+	}{
+		{
+			name: "defaults",
+			opts: options.OTLPRetryOptions{Enabled: true},
+			want: otlptracegrpc.RetryConfig{Enabled: true, InitialInterval: 5 * time.Second, MaxInterval: 30 * time.Second, MaxElapsedTime: time.Minute},
+		},
+		{
+			name: "custom",
+			opts: options.OTLPRetryOptions{Enabled: true, InitialIntervalSeconds: 1, MaxIntervalSeconds: 10, MaxElapsedTimeSeconds: 300},
+			want: otlptracegrpc.RetryConfig{Enabled: true, InitialInterval: time.Second, MaxInterval: 10 * time.Second, MaxElapsedTime: 5 * time.Minute},
+		},
+		{
+			name: "disabled",
+			opts: options.OTLPRetryOptions{MaxIntervalSeconds: -1},
+			want: otlptracegrpc.RetryConfig{InitialInterval: 5 * time.Second, MaxInterval: 30 * time.Second, MaxElapsedTime: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryConfig(tt.opts); got != tt.want {
+				t.Errorf("retryConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
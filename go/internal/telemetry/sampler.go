@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ForcedSamplingAttribute marks spans sampled because of ContextWithForcedSampling.
+// The tail sampler keeps traces with such a span, like errored traces.
+const ForcedSamplingAttribute = "sampling.forced"
+
+// forcedSamplingKey is the context key set by ContextWithForcedSampling
+type forcedSamplingKey struct{}
+
+// ContextWithForcedSampling returns ctx marked so that spans started with it (and their children)
+// are recorded and sampled regardless of the configured sampler, e.g. to debug a single request
+func ContextWithForcedSampling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcedSamplingKey{}, true)
+}
+
+// IsForcedSampling reports whether ctx was marked with ContextWithForcedSampling
+func IsForcedSampling(ctx context.Context) bool {
+	forced, _ := ctx.Value(forcedSamplingKey{}).(bool)
+	return forced
+}
+
+// funcSampler adapts a user-provided options.SamplerFunc to the sdktrace.Sampler interface
+type funcSampler struct {
+	fn options.SamplerFunc
+}
+
+// forcingSampler samples spans started with ContextWithForcedSampling and defers to base otherwise
+type forcingSampler struct {
+	base sdktrace.Sampler
+}
+
+// newSampler returns the sampler configured in the tracing options (AlwaysSample by default).
+// Without a custom sampler, OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG are honored, then SampleRatio.
+// Forced sampling (ContextWithForcedSampling) takes precedence over either.
+func newSampler(opts options.TracingTelemetryOptions) sdktrace.Sampler {
+	var base sdktrace.Sampler = &funcSampler{fn: opts.Sampler}
+	if opts.Sampler == nil {
+		base = envSampler(opts.SampleRatio)
+	}
+	return &forcingSampler{base: base}
+}
+
+// envSampler returns the sampler named in OTEL_TRACES_SAMPLER. If it is unset or not one of the built-in
+// samplers, traces are sampled by sampleRatio (parent-based, 0 samples no new trace), or every span if
+// sampleRatio is nil. The ratio samplers read OTEL_TRACES_SAMPLER_ARG (default: 1.0).
+func envSampler(sampleRatio *float64) sdktrace.Sampler {
+	ratio := 1.0
+	if arg, err := strconv.ParseFloat(os.Getenv("OTEL_TRACES_SAMPLER_ARG"), 64); err == nil && arg >= 0 && arg <= 1 {
+		ratio = arg
+	}
+
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_SAMPLER")) {
+	case "always_on":
+		return sdktrace.AlwaysSample()
+	case "always_off":
+		return sdktrace.NeverSample()
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio)
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample())
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	default:
+		if sampleRatio == nil {
+			return sdktrace.AlwaysSample()
+		}
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*sampleRatio))
+	}
+}
+
+// ShouldSample records and samples forced spans, marked with ForcedSamplingAttribute
+func (s *forcingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if !IsForcedSampling(p.ParentContext) {
+		return s.base.ShouldSample(p)
+	}
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordAndSample,
+		Attributes: []attribute.KeyValue{attribute.Bool(ForcedSamplingAttribute, true)},
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description returns the name of the sampler
+func (s *forcingSampler) Description() string {
+	return "PulseForcingSampler{" + s.base.Description() + "}"
+}
+
+// ShouldSample calls the user function with the span name and initial attributes
+func (s *funcSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+
+	attrs := make(map[string]interface{}, len(p.Attributes))
+	for _, kv := range p.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+
+	decision := sdktrace.Drop
+	if s.fn(options.SamplingParams{
+		SpanName:      p.Name,
+		TraceID:       p.TraceID.String(),
+		ParentSampled: parent.IsSampled(),
+		Attributes:    attrs,
+	}) {
+		decision = sdktrace.RecordAndSample
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: parent.TraceState(),
+	}
+}
+
+// Description returns the name of the sampler
+func (s *funcSampler) Description() string {
+	return "PulseFuncSampler"
+}
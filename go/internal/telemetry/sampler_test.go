@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEnvSampler(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler string
+		arg     string
+		ratio   *float64
+		want    sdktrace.Sampler
+	}{
+		{name: "default", want: sdktrace.AlwaysSample()},
+		{name: "sample ratio", ratio: options.Ratio(0.1), want: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.1))},
+		{name: "zero ratio", ratio: options.Ratio(0), want: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0))},
+		{name: "always_off", sampler: "always_off", ratio: options.Ratio(0.1), want: sdktrace.NeverSample()},
+		{name: "always_on", sampler: "ALWAYS_ON", want: sdktrace.AlwaysSample()},
+		{name: "traceidratio", sampler: "traceidratio", arg: "0.25", want: sdktrace.TraceIDRatioBased(0.25)},
+		{name: "traceidratio without arg", sampler: "traceidratio", want: sdktrace.TraceIDRatioBased(1)},
+		{name: "invalid arg", sampler: "traceidratio", arg: "2", want: sdktrace.TraceIDRatioBased(1)},
+		{name: "parentbased_always_off", sampler: "parentbased_always_off", want: sdktrace.ParentBased(sdktrace.NeverSample())},
+		{name: "parentbased_traceidratio", sampler: "parentbased_traceidratio", arg: "0.5", want: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.5))},
+		{name: "unknown sampler", sampler: "jaeger_remote", ratio: options.Ratio(0.1), want: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+
+			if got := envSampler(tt.ratio).Description(); got != tt.want.Description() {
+				t.Errorf("envSampler() = %s, want %s", got, tt.want.Description())
+			}
+		})
+	}
+}
+
+func TestForcedSampling(t *testing.T) {
+	t.Setenv("OTEL_TRACES_SAMPLER", "always_off")
+	sampler := newSampler(options.TracingTelemetryOptions{})
+
+	params := sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}, Name: "op"}
+	if got := sampler.ShouldSample(params).Decision; got != sdktrace.Drop {
+		t.Errorf("unforced decision = %v, want Drop", got)
+	}
+
+	params.ParentContext = ContextWithForcedSampling(context.Background())
+	result := sampler.ShouldSample(params)
+	if result.Decision != sdktrace.RecordAndSample {
+		t.Errorf("forced decision = %v, want RecordAndSample", result.Decision)
+	}
+	if len(result.Attributes) != 1 || string(result.Attributes[0].Key) != ForcedSamplingAttribute {
+		t.Errorf("forced attributes = %v, want %s", result.Attributes, ForcedSamplingAttribute)
+	}
+}
+
+func TestFuncSampler(t *testing.T) {
+	sampler := newSampler(options.TracingTelemetryOptions{
+		Sampler: func(p options.SamplingParams) bool { return p.SpanName != "health" },
+	})
+
+	for name, want := range map[string]sdktrace.SamplingDecision{"health": sdktrace.Drop, "order": sdktrace.RecordAndSample} {
+		params := sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}, Name: name}
+		if got := sampler.ShouldSample(params).Decision; got != want {
+			t.Errorf("decision for %s = %v, want %v", name, got, want)
+		}
+	}
+}
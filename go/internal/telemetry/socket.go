@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"google.golang.org/grpc"
+)
+
+// defaultOTLPHost is the collector host used when OTLPOptions.Host is unset.
+const defaultOTLPHost = "localhost"
+
+// otlpEndpoint returns the gRPC target of the collector and the dial options needed to reach it.
+// With OTLPOptions.UnixSocket, the target is the socket path and a dialer connects over the
+// Unix domain socket instead of TCP; otherwise it is Host:Port, with an unset Host meaning localhost.
+func otlpEndpoint(opts options.OTLPOptions) (string, []grpc.DialOption) {
+	if opts.UnixSocket == "" {
+		host := opts.Host
+		if host == "" {
+			host = defaultOTLPHost
+		}
+		return fmt.Sprintf("%s:%d", host, opts.Port), nil
+	}
+
+	path := strings.TrimPrefix(opts.UnixSocket, "unix://")
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+	return "passthrough:///" + path, []grpc.DialOption{grpc.WithContextDialer(dialer)}
+}
+
+// validateOTLPEndpoint rejects a Unix socket combined with an explicitly set TCP host.
+// Port is ignored with a socket.
+func validateOTLPEndpoint(opts options.OTLPOptions) error {
+	if opts.UnixSocket == "" {
+		return nil
+	}
+	if opts.Host != "" {
+		return errors.New("OTLP unixSocket and host are mutually exclusive, set only one")
+	}
+	return nil
+}
+
+// signalOTLP returns the OTLP options of one signal: the shared options with the signal's endpoint
+// overrides applied. Host and UnixSocket are mutually exclusive, so setting either replaces both.
+func signalOTLP(shared options.OTLPOptions, endpoint options.OTLPEndpointOptions) options.OTLPOptions {
+	opts := shared
+	if endpoint.Host != "" || endpoint.UnixSocket != "" {
+		opts.Host, opts.UnixSocket = endpoint.Host, endpoint.UnixSocket
+	}
+	if endpoint.Port != 0 {
+		opts.Port = endpoint.Port
+	}
+	return opts
+}
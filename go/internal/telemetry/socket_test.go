@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+func TestOTLPEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     options.OTLPOptions
+		want     string
+		wantDial bool
+	}{
+		{name: "host and port", opts: options.OTLPOptions{Host: "collector", Port: 4317}, want: "collector:4317"},
+		{name: "default host", opts: options.OTLPOptions{Port: 4317}, want: "localhost:4317"},
+		{name: "socket path", opts: options.OTLPOptions{UnixSocket: "/run/otel/otlp.sock", Port: 4317}, want: "passthrough:////run/otel/otlp.sock", wantDial: true},
+		{name: "socket URL", opts: options.OTLPOptions{UnixSocket: "unix:///run/otel/otlp.sock"}, want: "passthrough:////run/otel/otlp.sock", wantDial: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dialOpts := otlpEndpoint(tt.opts)
+			if got != tt.want {
+				t.Errorf("otlpEndpoint() = %q, want %q", got, tt.want)
+			}
+			if (len(dialOpts) > 0) != tt.wantDial {
+				t.Errorf("otlpEndpoint() returned %d dial options, want dialer %v", len(dialOpts), tt.wantDial)
+			}
+		})
+	}
+}
+
+func TestValidateOTLPEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    options.OTLPOptions
+		wantErr bool
+	}{
+		{name: "host only", opts: options.OTLPOptions{Host: "collector"}},
+		{name: "socket only", opts: options.OTLPOptions{UnixSocket: "/run/otel/otlp.sock", Port: 4317}},
+		{name: "socket and host", opts: options.OTLPOptions{UnixSocket: "/run/otel/otlp.sock", Host: "collector"}, wantErr: true},
+		{name: "socket and localhost", opts: options.OTLPOptions{UnixSocket: "/run/otel/otlp.sock", Host: "localhost"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOTLPEndpoint(tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("validateOTLPEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignalOTLP(t *testing.T) {
+	shared := options.OTLPOptions{Host: "collector", Port: 4317, Enabled: true}
+
+	tests := []struct {
+		name     string
+		endpoint options.OTLPEndpointOptions
+		want     options.OTLPOptions
+	}{
+		{name: "no override", want: shared},
+		{name: "port", endpoint: options.OTLPEndpointOptions{Port: 4318}, want: options.OTLPOptions{Host: "collector", Port: 4318, Enabled: true}},
+		{name: "host", endpoint: options.OTLPEndpointOptions{Host: "tempo"}, want: options.OTLPOptions{Host: "tempo", Port: 4317, Enabled: true}},
+		{name: "socket replaces host", endpoint: options.OTLPEndpointOptions{UnixSocket: "/run/otel/otlp.sock"}, want: options.OTLPOptions{UnixSocket: "/run/otel/otlp.sock", Port: 4317, Enabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signalOTLP(shared, tt.endpoint); got != tt.want {
+				t.Errorf("signalOTLP() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
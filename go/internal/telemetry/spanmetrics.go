@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanDurationMetric is the histogram spans are recorded to when RecordSpanDurations is enabled
+const spanDurationMetric = "span.duration"
+
+// spanDurationProcessor records the duration of every ended span into a latency histogram
+// keyed by span name and status, giving RED metrics without separate instrumentation
+type spanDurationProcessor struct {
+	histogram metric.Float64Histogram
+}
+
+// newSpanDurationProcessor creates the processor using the given meter
+func newSpanDurationProcessor(meter metric.Meter) (*spanDurationProcessor, error) {
+	histogram, err := meter.Float64Histogram(spanDurationMetric,
+		metric.WithDescription("Duration of spans by name and status"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s histogram: %w", spanDurationMetric, err)
+	}
+	return &spanDurationProcessor{histogram: histogram}, nil
+}
+
+// OnStart is a no-op, durations are recorded on end
+func (p *spanDurationProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd records the span duration in milliseconds
+func (p *spanDurationProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	duration := float64(s.EndTime().Sub(s.StartTime())) / float64(time.Millisecond)
+	p.histogram.Record(context.Background(), duration, metric.WithAttributes(
+		attribute.String("span.name", s.Name()),
+		attribute.String("span.status", s.Status().Code.String()),
+	))
+}
+
+// Shutdown is a no-op, the meter provider is shut down separately
+func (p *spanDurationProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush is a no-op, the meter provider is flushed separately
+func (p *spanDurationProcessor) ForceFlush(context.Context) error { return nil }
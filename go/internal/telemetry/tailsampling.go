@@ -0,0 +1,193 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Default tail sampling limits
+const (
+	defaultTailSamplingWindow    = 10 * time.Second
+	defaultTailSamplingMaxTraces = 10000
+)
+
+// tailSampler buffers ended spans per trace and only passes a trace on to the next processor
+// (the OTLP batcher) if one of its spans has error status or was force-sampled (ForcedSamplingAttribute). A trace is decided when its local
+// root span ends, when it has been buffered for the window, or when the buffer is full
+// (oldest trace first).
+type tailSampler struct {
+	next      sdktrace.SpanProcessor
+	window    time.Duration
+	maxTraces int
+
+	mu     sync.Mutex
+	traces map[trace.TraceID]*tailTrace
+	order  []trace.TraceID // Buffered traces, oldest first
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// tailTrace holds the ended spans of one buffered trace
+type tailTrace struct {
+	spans    []sdktrace.ReadOnlySpan
+	keep     bool // A span errored or was force-sampled
+	deadline time.Time
+}
+
+// newTailSampler creates a tail sampler in front of next and starts the window expiry loop
+func newTailSampler(next sdktrace.SpanProcessor, opts options.TailSamplingOptions) *tailSampler {
+	window := time.Duration(opts.WindowSeconds) * time.Second
+	if window <= 0 {
+		window = defaultTailSamplingWindow
+	}
+	maxTraces := opts.MaxTraces
+	if maxTraces <= 0 {
+		maxTraces = defaultTailSamplingMaxTraces
+	}
+
+	t := &tailSampler{
+		next:      next,
+		window:    window,
+		maxTraces: maxTraces,
+		traces:    make(map[trace.TraceID]*tailTrace),
+		done:      make(chan struct{}),
+	}
+	go t.expireLoop()
+	return t
+}
+
+// OnStart forwards to the next processor
+func (t *tailSampler) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	t.next.OnStart(ctx, s)
+}
+
+// OnEnd buffers the span and decides its trace once the local root span has ended
+func (t *tailSampler) OnEnd(s sdktrace.ReadOnlySpan) {
+	traceID := s.SpanContext().TraceID()
+
+	t.mu.Lock()
+	tt, ok := t.traces[traceID]
+	if !ok {
+		// Make room by deciding the oldest trace
+		var evicted *tailTrace
+		if len(t.order) >= t.maxTraces {
+			evicted = t.remove(t.order[0])
+		}
+
+		tt = &tailTrace{deadline: time.Now().Add(t.window)}
+		t.traces[traceID] = tt
+		t.order = append(t.order, traceID)
+
+		if evicted != nil {
+			defer t.decide(evicted)
+		}
+	}
+	tt.spans = append(tt.spans, s)
+	if s.Status().Code == codes.Error || isForcedSpan(s) {
+		tt.keep = true
+	}
+
+	// The local root ends last, so the trace is complete in this process
+	var complete *tailTrace
+	if parent := s.Parent(); !parent.IsValid() || parent.IsRemote() {
+		complete = t.remove(traceID)
+	}
+	t.mu.Unlock()
+
+	if complete != nil {
+		t.decide(complete)
+	}
+}
+
+// remove takes a trace out of the buffer. Must be called with t.mu held.
+func (t *tailSampler) remove(traceID trace.TraceID) *tailTrace {
+	tt := t.traces[traceID]
+	delete(t.traces, traceID)
+	for i, id := range t.order {
+		if id == traceID {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+	return tt
+}
+
+// decide passes the spans of a kept trace to the next processor and drops the others
+func (t *tailSampler) decide(tt *tailTrace) {
+	if tt == nil || !tt.keep {
+		return
+	}
+	for _, s := range tt.spans {
+		t.next.OnEnd(s)
+	}
+}
+
+// expireLoop decides traces whose window has elapsed (e.g., traces whose root is in another process)
+func (t *tailSampler) expireLoop() {
+	ticker := time.NewTicker(t.window / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case now := <-ticker.C:
+			t.mu.Lock()
+			var expired []*tailTrace
+			for len(t.order) > 0 && !t.traces[t.order[0]].deadline.After(now) {
+				expired = append(expired, t.remove(t.order[0]))
+			}
+			t.mu.Unlock()
+
+			for _, tt := range expired {
+				t.decide(tt)
+			}
+		}
+	}
+}
+
+// flushPending decides every buffered trace, exporting the kept ones
+func (t *tailSampler) flushPending() {
+	t.mu.Lock()
+	pending := make([]*tailTrace, 0, len(t.order))
+	for _, id := range t.order {
+		pending = append(pending, t.traces[id])
+	}
+	t.traces = make(map[trace.TraceID]*tailTrace)
+	t.order = nil
+	t.mu.Unlock()
+
+	for _, tt := range pending {
+		t.decide(tt)
+	}
+}
+
+// Shutdown decides the buffered traces, stops the expiry loop and shuts down the next processor
+func (t *tailSampler) Shutdown(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.done) })
+	t.flushPending()
+	return t.next.Shutdown(ctx)
+}
+
+// ForceFlush decides the buffered traces and flushes the next processor
+func (t *tailSampler) ForceFlush(ctx context.Context) error {
+	t.flushPending()
+	return t.next.ForceFlush(ctx)
+}
+
+// isForcedSpan reports whether the span was sampled by ContextWithForcedSampling
+func isForcedSpan(s sdktrace.ReadOnlySpan) bool {
+	for _, kv := range s.Attributes() {
+		if kv.Key == ForcedSamplingAttribute {
+			return kv.Value.AsBool()
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestTailSampler returns a tracer whose spans are tail sampled into the returned recorder
+func newTestTailSampler(t *testing.T, opts options.TailSamplingOptions) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newTailSampler(recorder, opts)))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, recorder
+}
+
+func TestTailSamplerKeepsErroredTraces(t *testing.T) {
+	provider, recorder := newTestTailSampler(t, options.TailSamplingOptions{Enabled: true})
+	tracer := provider.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "ok")
+	_, child := tracer.Start(ctx, "ok.child")
+	child.End()
+	root.End()
+
+	ctx, root = tracer.Start(context.Background(), "failed")
+	_, child = tracer.Start(ctx, "failed.child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	root.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("exported %d spans, want the 2 spans of the errored trace", len(ended))
+	}
+	for _, s := range ended {
+		if s.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("exported span %s of a trace without errors", s.Name())
+		}
+	}
+}
+
+func TestTailSamplerKeepsForcedTraces(t *testing.T) {
+	provider, recorder := newTestTailSampler(t, options.TailSamplingOptions{Enabled: true})
+	_, span := provider.Tracer("test").Start(context.Background(), "forced")
+	// The forcing sampler adds this attribute at span start
+	span.SetAttributes(attribute.Bool(ForcedSamplingAttribute, true))
+	span.End()
+
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("exported %d spans, want the forced span", got)
+	}
+}
+
+func TestTailSamplerEvictsOldestTrace(t *testing.T) {
+	provider, recorder := newTestTailSampler(t, options.TailSamplingOptions{Enabled: true, MaxTraces: 1})
+	tracer := provider.Tracer("test")
+
+	// Only children end, so both traces stay buffered until the first is evicted
+	ctx, first := tracer.Start(context.Background(), "first")
+	_, child := tracer.Start(ctx, "first.child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+
+	ctx, second := tracer.Start(context.Background(), "second")
+	_, child = tracer.Start(ctx, "second.child")
+	child.End()
+
+	if ended := recorder.Ended(); len(ended) != 1 || ended[0].Name() != "first.child" {
+		t.Errorf("exported %v, want the evicted errored trace", ended)
+	}
+
+	first.End()
+	second.End()
+}
+
+func TestTailSamplerShutdownFlushes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newTailSampler(recorder, options.TailSamplingOptions{Enabled: true})))
+	tracer := provider.Tracer("test")
+
+	ctx, _ := tracer.Start(context.Background(), "root") // Never ended, so the trace stays buffered
+	_, child := tracer.Start(ctx, "child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("exported %d spans on shutdown, want the buffered errored span", got)
+	}
+}
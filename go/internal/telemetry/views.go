@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Default limits for base-2 exponential histograms, matching the OpenTelemetry SDK defaults
+const (
+	exponentialHistogramMaxSize  = 160
+	exponentialHistogramMaxScale = 20
+)
+
+// validateViews rejects configured views the SDK cannot apply
+func validateViews(opts options.MetricsTelemetryOptions) error {
+	for i, view := range opts.Views {
+		if view.MatchName == "" {
+			return fmt.Errorf("metric view %d has no matchName", i)
+		}
+		if view.Rename != "" && strings.ContainsAny(view.MatchName, "*?") {
+			return fmt.Errorf("metric view %q: rename requires an exact matchName", view.MatchName)
+		}
+	}
+	return nil
+}
+
+// buildViews creates the metric views configured in the metrics options.
+// The SDK creates one stream per matching view, so an instrument matched by both a configured view and
+// the exponential histogram names gets a single stream: the configured view with the exponential
+// aggregation, unless it drops the instrument. The views are checked by validateViews first.
+func buildViews(opts options.MetricsTelemetryOptions) []sdkmetric.View {
+	// Exponential histograms, either for every histogram or for matching instrument names
+	names := opts.ExponentialHistogramNames
+	if opts.ExponentialHistograms {
+		names = []string{"*"}
+	}
+	exponential := make([]sdkmetric.View, len(names))
+	for i, name := range names {
+		exponential[i] = sdkmetric.NewView(
+			sdkmetric.Instrument{Name: name, Kind: sdkmetric.InstrumentKindHistogram},
+			sdkmetric.Stream{Aggregation: exponentialAggregation()},
+		)
+	}
+	isExponential := func(inst sdkmetric.Instrument) bool {
+		for _, view := range exponential {
+			if _, ok := view(inst); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Configured views for renaming, dropping and attribute filtering
+	configured := make([]sdkmetric.View, len(opts.Views))
+	for i, view := range opts.Views {
+		stream := sdkmetric.Stream{Name: view.Rename}
+		if view.Drop {
+			stream.Aggregation = sdkmetric.AggregationDrop{}
+		}
+		if len(view.KeepAttributes) > 0 {
+			keys := make([]attribute.Key, len(view.KeepAttributes))
+			for i, key := range view.KeepAttributes {
+				keys[i] = attribute.Key(key)
+			}
+			stream.AttributeFilter = attribute.NewAllowKeysFilter(keys...)
+		}
+		configured[i] = sdkmetric.NewView(sdkmetric.Instrument{Name: view.MatchName}, stream)
+	}
+	isConfigured := func(inst sdkmetric.Instrument) bool {
+		for _, view := range configured {
+			if _, ok := view(inst); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var views []sdkmetric.View
+	for _, view := range configured {
+		views = append(views, func(inst sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+			stream, ok := view(inst)
+			if ok && stream.Aggregation == nil && isExponential(inst) {
+				stream.Aggregation = exponentialAggregation()
+			}
+			return stream, ok
+		})
+	}
+	for _, view := range exponential {
+		views = append(views, func(inst sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+			if isConfigured(inst) {
+				return sdkmetric.Stream{}, false
+			}
+			return view(inst)
+		})
+	}
+	return views
+}
+
+// exponentialAggregation returns the base-2 exponential histogram aggregation with the default limits
+func exponentialAggregation() sdkmetric.Aggregation {
+	return sdkmetric.AggregationBase2ExponentialHistogram{
+		MaxSize:  exponentialHistogramMaxSize,
+		MaxScale: exponentialHistogramMaxScale,
+	}
+}
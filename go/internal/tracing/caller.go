@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Function name prefixes of the frames skipped when looking for the caller of Start:
+// this package (Start, Trace, ...) and the pulse facade
+const (
+	tracingFuncPrefix = "github.com/machanirobotics/pulse/go/internal/tracing."
+	facadeFuncPrefix  = "github.com/machanirobotics/pulse/go."
+)
+
+// callerAttributes returns the code.function, code.filepath and code.lineno attributes of the
+// first frame outside the tracing package and the pulse facade (TracingOptions.RecordCaller)
+func callerAttributes() []attribute.KeyValue {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:]) // Skip runtime.Callers and callerAttributes
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, tracingFuncPrefix) && !strings.HasPrefix(frame.Function, facadeFuncPrefix) {
+			return []attribute.KeyValue{
+				attribute.String("code.function", frame.Function),
+				attribute.String("code.filepath", frame.File),
+				attribute.Int("code.lineno", frame.Line),
+			}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
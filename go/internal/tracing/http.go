@@ -0,0 +1,186 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HTTPMiddleware returns an HTTP middleware that traces each request in a span named after the route
+// pattern of the request (http.Request.Pattern, e.g. "GET /orders/{id}"), set as the http.route attribute.
+// Patterns are set by http.ServeMux; if the middleware wraps the mux, the span is renamed once the mux has
+// routed the request. Requests without a pattern (other routers, unmatched paths) are named "METHOD /path",
+// rewritten by TracingOptions.SpanNameNormalizer if set, which keeps IDs out of span names (see NormalizeSpanName).
+// The caller's trace is continued from the traceparent/baggage headers. Headers listed in
+// TracingOptions.HeaderAttributes (e.g., X-Tenant-ID) become span attributes and baggage members,
+// so logs in the handler carry them too. 5xx responses set the span status to error.
+// A true TracingOptions.DebugHeader value force-samples the request span and its children.
+// Wrap RecoveryMiddleware with it, so recovered panics are recorded on the request span.
+func (t *Tracing) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		attrs := map[string]interface{}{
+			"http.method": r.Method,
+			"http.path":   r.URL.Path,
+		}
+		ctx = t.headerAttributes(ctx, r.Header, attrs)
+		if t.debugRequested(r.Header) {
+			ctx = telemetry.ContextWithForcedSampling(ctx)
+		}
+
+		name := r.Method + " " + r.URL.Path
+		if r.Pattern != "" {
+			name = routeSpanName(r.Method, r.Pattern)
+			attrs["http.route"] = r.Pattern
+		}
+		ctx, span := t.startWithAttrs(ctx, name, attrs, false)
+		defer span.End()
+
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		req := r.WithContext(ctx)
+		next.ServeHTTP(wrapResponseWriter(w, rw), req)
+
+		// http.ServeMux sets the pattern of the request it routed
+		if req.Pattern != "" && req.Pattern != r.Pattern {
+			span.name = t.spanName(routeSpanName(r.Method, req.Pattern))
+			span.span.SetName(span.name)
+			span.SetAttribute("http.route", req.Pattern)
+		}
+
+		span.SetAttribute("http.status_code", rw.status)
+		if rw.status >= http.StatusInternalServerError {
+			span.span.SetStatus(codes.Error, http.StatusText(rw.status))
+		}
+	})
+}
+
+// routeSpanName returns the span name of a request routed by pattern. Patterns without a method
+// (e.g. "/orders/{id}") are prefixed with the request method.
+func routeSpanName(method, pattern string) string {
+	if strings.HasPrefix(pattern, "/") {
+		return method + " " + pattern
+	}
+	return pattern
+}
+
+// headerAttributes adds the headers mapped in TracingOptions.HeaderAttributes to attrs and
+// returns ctx with them as baggage members. Missing headers and values that are not valid
+// baggage are only skipped for baggage.
+func (t *Tracing) headerAttributes(ctx context.Context, header http.Header, attrs map[string]interface{}) context.Context {
+	if len(t.opts.HeaderAttributes) == 0 {
+		return ctx
+	}
+
+	names := make([]string, 0, len(t.opts.HeaderAttributes))
+	for name := range t.opts.HeaderAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bag := baggage.FromContext(ctx)
+	for _, name := range names {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+
+		key := t.opts.HeaderAttributes[name]
+		attrs[key] = value
+		if member, err := baggage.NewMemberRaw(key, value); err == nil {
+			if updated, err := bag.SetMember(member); err == nil {
+				bag = updated
+			}
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// debugRequested reports whether the request asks for forced sampling with TracingOptions.DebugHeader
+func (t *Tracing) debugRequested(header http.Header) bool {
+	if t.opts.DebugHeader == "" {
+		return false
+	}
+	debug, err := strconv.ParseBool(header.Get(t.opts.DebugHeader))
+	return err == nil && debug
+}
+
+// statusRecorder captures the response status code for the request span
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and forwards it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// wrapResponseWriter returns rec with the optional http.Flusher and http.Hijacker interfaces of w,
+// so streaming responses (SSE) and connection upgrades (WebSocket) still work behind the middleware
+func wrapResponseWriter(w http.ResponseWriter, rec *statusRecorder) http.ResponseWriter {
+	_, flusher := w.(http.Flusher)
+	_, hijacker := w.(http.Hijacker)
+	switch {
+	case flusher && hijacker:
+		return &flushHijackRecorder{rec}
+	case flusher:
+		return &flushRecorder{rec}
+	case hijacker:
+		return &hijackRecorder{rec}
+	default:
+		return rec
+	}
+}
+
+// flush forwards to the underlying http.Flusher
+func (r *statusRecorder) flush() {
+	r.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack forwards to the underlying http.Hijacker. The span records 101 Switching Protocols,
+// since the response is then written on the connection directly.
+func (r *statusRecorder) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := r.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// flushRecorder is a statusRecorder of an http.Flusher
+type flushRecorder struct{ *statusRecorder }
+
+// Flush sends buffered data to the client
+func (r *flushRecorder) Flush() { r.flush() }
+
+// hijackRecorder is a statusRecorder of an http.Hijacker
+type hijackRecorder struct{ *statusRecorder }
+
+// Hijack lets the handler take over the connection
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) { return r.hijack() }
+
+// flushHijackRecorder is a statusRecorder of an http.Flusher and http.Hijacker (e.g., an HTTP/1.x response)
+type flushHijackRecorder struct{ *statusRecorder }
+
+// Flush sends buffered data to the client
+func (r *flushHijackRecorder) Flush() { r.flush() }
+
+// Hijack lets the handler take over the connection
+func (r *flushHijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) { return r.hijack() }
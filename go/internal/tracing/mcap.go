@@ -1,7 +1,15 @@
 package tracing
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // SpanData represents a trace span for MCAP logging
@@ -16,3 +24,142 @@ type SpanData struct {
 	Duration    int64                  `json:"duration_ns"`
 	ServiceName string                 `json:"service_name"`
 }
+
+// SpanInterval represents a finished span as a timeline interval following the
+// mahcanirobotics.span_interval schema
+type SpanInterval struct {
+	Start       FoxgloveTimestamp      `json:"start"`
+	End         FoxgloveTimestamp      `json:"end"`
+	DurationNs  int64                  `json:"duration_ns"`
+	Name        string                 `json:"name"`
+	TraceID     string                 `json:"trace_id"`
+	SpanID      string                 `json:"span_id"`
+	ParentID    string                 `json:"parent_id,omitempty"`
+	Status      string                 `json:"status"` // unset, ok, error
+	Color       string                 `json:"color"`  // Hex color for the status
+	ServiceName string                 `json:"service_name"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// FoxgloveTimestamp represents a timestamp in Foxglove format
+type FoxgloveTimestamp struct {
+	Sec  uint32 `json:"sec"`
+	Nsec uint32 `json:"nsec"`
+}
+
+// Status colors for timeline intervals
+const (
+	statusColorUnset = "#9e9e9e"
+	statusColorOK    = "#4caf50"
+	statusColorError = "#f44336"
+)
+
+// timelineTopic is the MCAP topic spans are written to.
+// Shared writers get a per-service topic so instances don't collide.
+const timelineTopic = "/traces/timeline"
+
+// SpanMcapWriter writes finished spans to MCAP as timeline intervals.
+// It is a span processor, registered on the tracer provider with Telemetry.RegisterSpanProcessor,
+// so every span is written once when the SDK ends it.
+type SpanMcapWriter struct {
+	unifiedWriter *foxglove.UnifiedMcapWriter
+	channelID     uint16
+	serviceName   string
+}
+
+// NewSpanMcapWriter creates a span writer using the unified MCAP writer
+func NewSpanMcapWriter(serviceOpts options.ServiceOptions, unifiedWriter *foxglove.UnifiedMcapWriter) (*SpanMcapWriter, error) {
+	metadata := map[string]string{
+		"service":     serviceOpts.Name,
+		"version":     serviceOpts.Version,
+		"environment": string(serviceOpts.Environment),
+	}
+
+	topic := timelineTopic
+	if unifiedWriter.IsShared() {
+		topic = fmt.Sprintf("%s/%s", timelineTopic, serviceOpts.Name)
+	}
+
+	channelID, err := unifiedWriter.CreateSpanChannel(topic, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create span channel: %w", err)
+	}
+
+	return &SpanMcapWriter{
+		unifiedWriter: unifiedWriter,
+		channelID:     channelID,
+		serviceName:   serviceOpts.Name,
+	}, nil
+}
+
+// OnStart is a no-op, spans are written when they end
+func (w *SpanMcapWriter) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd writes the finished span to the timeline
+func (w *SpanMcapWriter) OnEnd(ro sdktrace.ReadOnlySpan) {
+	_ = w.WriteSpan(ro) // Ignore MCAP errors, the span is still exported via OTLP
+}
+
+// Shutdown is a no-op, the MCAP writer is closed by Pulse
+func (w *SpanMcapWriter) Shutdown(context.Context) error { return nil }
+
+// ForceFlush is a no-op, intervals are written as spans end
+func (w *SpanMcapWriter) ForceFlush(context.Context) error { return nil }
+
+// WriteSpan writes a finished span as a timeline interval
+func (w *SpanMcapWriter) WriteSpan(ro sdktrace.ReadOnlySpan) error {
+	if w.unifiedWriter.IsClosed() {
+		return nil
+	}
+
+	start, end := ro.StartTime(), ro.EndTime()
+	if end.IsZero() {
+		return nil
+	}
+
+	status, color := "unset", statusColorUnset
+	switch ro.Status().Code {
+	case codes.Ok:
+		status, color = "ok", statusColorOK
+	case codes.Error:
+		status, color = "error", statusColorError
+	}
+
+	interval := SpanInterval{
+		Start:       toFoxgloveTimestamp(start),
+		End:         toFoxgloveTimestamp(end),
+		DurationNs:  end.Sub(start).Nanoseconds(),
+		Name:        ro.Name(),
+		TraceID:     ro.SpanContext().TraceID().String(),
+		SpanID:      ro.SpanContext().SpanID().String(),
+		Status:      status,
+		Color:       color,
+		ServiceName: w.serviceName,
+	}
+	if ro.Parent().HasSpanID() {
+		interval.ParentID = ro.Parent().SpanID().String()
+	}
+	if attrs := ro.Attributes(); len(attrs) > 0 {
+		interval.Attributes = make(map[string]interface{}, len(attrs))
+		for _, kv := range attrs {
+			interval.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+
+	data, err := json.Marshal(interval)
+	if err != nil {
+		return fmt.Errorf("failed to marshal span interval: %w", err)
+	}
+
+	// Log the interval at its start time so it lines up on the timeline
+	startNano := uint64(start.UnixNano())
+	return w.unifiedWriter.WriteMessage(w.channelID, data, startNano, uint64(end.UnixNano()))
+}
+
+// toFoxgloveTimestamp converts a time to Foxglove format
+func toFoxgloveTimestamp(t time.Time) FoxgloveTimestamp {
+	return FoxgloveTimestamp{
+		Sec:  uint32(t.Unix()),
+		Nsec: uint32(t.Nanosecond()),
+	}
+}
@@ -0,0 +1,26 @@
+package tracing
+
+import "regexp"
+
+// Patterns of the dynamic name segments replaced by NormalizeSpanName
+var (
+	uuidSegment   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	numberSegment = regexp.MustCompile(`\b[0-9]+\b`)
+)
+
+// NormalizeSpanName replaces UUIDs with {uuid} and numbers with {id}, so span names built from
+// request data keep a bounded cardinality, e.g. "GET /users/42/orders/3f2b...-..." becomes
+// "GET /users/{id}/orders/{uuid}". Digits inside words ("v2", "ipv4") are kept.
+// Use it as TracingOptions.SpanNameNormalizer.
+func NormalizeSpanName(name string) string {
+	name = uuidSegment.ReplaceAllString(name, "{uuid}")
+	return numberSegment.ReplaceAllString(name, "{id}")
+}
+
+// spanName applies TracingOptions.SpanNameNormalizer, if set
+func (t *Tracing) spanName(name string) string {
+	if t.opts.SpanNameNormalizer == nil {
+		return name
+	}
+	return t.opts.SpanNameNormalizer(name)
+}
@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceOptions are the span start options of TraceWith
+type TraceOptions struct {
+	Kind       trace.SpanKind         // Span kind (default: internal)
+	Links      []trace.Link           // Links to related spans, e.g. the producers of a batch of messages
+	Attributes map[string]interface{} // Attributes added after the data struct's, overriding the same keys
+	StartTime  time.Time              // Start timestamp, e.g. when the work was queued (default: now)
+}
+
+// startOptions converts the options to span start options (the attributes are added by start)
+func (o TraceOptions) startOptions() []trace.SpanStartOption {
+	var opts []trace.SpanStartOption
+	if o.Kind != trace.SpanKindUnspecified {
+		opts = append(opts, trace.WithSpanKind(o.Kind))
+	}
+	if len(o.Links) > 0 {
+		opts = append(opts, trace.WithLinks(o.Links...))
+	}
+	if !o.StartTime.IsZero() {
+		opts = append(opts, trace.WithTimestamp(o.StartTime))
+	}
+	return opts
+}
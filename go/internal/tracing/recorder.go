@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SpanStub is a snapshot of an ended span returned by RecordedSpans
+type SpanStub = tracetest.SpanStub
+
+// RecordedSpans returns the spans that ended since startup (or the last ResetRecordedSpans), oldest
+// first, when TracingOptions.RecordSpans is set. Returns nil otherwise.
+func (t *Tracing) RecordedSpans() []SpanStub {
+	if t.recorder == nil {
+		return nil
+	}
+	return tracetest.SpanStubsFromReadOnlySpans(t.recorder.Ended())
+}
+
+// ResetRecordedSpans discards the spans recorded so far (see RecordedSpans)
+func (t *Tracing) ResetRecordedSpans() {
+	if t.recorder != nil {
+		t.recorder.Reset()
+	}
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/machanirobotics/pulse/go/internal/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanMetrics accumulates counts of work done within a span (items processed, bytes, ...).
+// When the span ends, each count is set as a span attribute and added to a counter of the
+// same name with a span.name attribute. Obtain it with Span.Metrics.
+type SpanMetrics struct {
+	mu     sync.Mutex
+	counts map[string]float64
+}
+
+// Inc increments the named count by one
+func (m *SpanMetrics) Inc(name string) {
+	m.Add(name, 1)
+}
+
+// Add adds n to the named count
+func (m *SpanMetrics) Add(name string, n float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name] += n
+}
+
+// flush sets the counts as span attributes and records them as counters, then resets them
+func (m *SpanMetrics) flush(span trace.Span, spanName string, meter *metrics.Metrics) {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = make(map[string]float64)
+	m.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	// Sort names so attributes and metrics are recorded in a stable order
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]attribute.KeyValue, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, attribute.Float64(name, counts[name]))
+	}
+	span.SetAttributes(attrs...)
+
+	if meter == nil {
+		return
+	}
+
+	// Record with the span in the context so exemplars link the measurements to it
+	meter = meter.WithContext(trace.ContextWithSpan(context.Background(), span))
+	for _, name := range names {
+		_ = meter.AddCounter(name, counts[name], attribute.String("span.name", spanName)) // Ignore metric errors, the span still has the counts
+	}
+}
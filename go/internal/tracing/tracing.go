@@ -2,13 +2,26 @@ package tracing
 
 import (
 	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
 	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/internal/metrics"
+	"github.com/machanirobotics/pulse/go/internal/tags"
 	"github.com/machanirobotics/pulse/go/internal/telemetry"
 	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -18,25 +31,66 @@ type Tracing struct {
 	mcap    *foxglove.UnifiedMcapWriter
 	opts    options.TracingOptions
 	service options.ServiceOptions
+	filter  *tags.Filter
+
+	// Attributes from TracingOptions.DefaultAttributes, added to every span
+	defaults []attribute.KeyValue
+
+	// Context bound with WithContext, used by CurrentSpan
+	ctx context.Context
+
+	// Records SpanMetrics counts when spans end (nil if metrics are not available)
+	metrics *metrics.Metrics
+
+	// Keeps ended spans in memory for RecordedSpans (nil unless TracingOptions.RecordSpans is set)
+	recorder *tracetest.SpanRecorder
 }
 
-// NewTracing creates a new Tracing instance
-func NewTracing(serviceOpts options.ServiceOptions, opts options.TracingOptions, mcap *foxglove.UnifiedMcapWriter, tracer *telemetry.Tracer) *Tracing {
-	return &Tracing{
-		tracer:  tracer,
-		mcap:    mcap,
-		opts:    opts,
-		service: serviceOpts,
+// NewTracing creates a new Tracing instance.
+// If recorder is provided, RecordedSpans returns the spans it recorded.
+func NewTracing(serviceOpts options.ServiceOptions, opts options.TracingOptions, mcap *foxglove.UnifiedMcapWriter, tracer *telemetry.Tracer, m *metrics.Metrics, recorder *tracetest.SpanRecorder) *Tracing {
+	t := &Tracing{
+		tracer:   tracer,
+		mcap:     mcap,
+		opts:     opts,
+		service:  serviceOpts,
+		filter:   tags.NewFilter(opts.Attributes),
+		ctx:      context.Background(),
+		metrics:  m,
+		recorder: recorder,
 	}
+	t.defaults = defaultAttributes(t.filter, opts.DefaultAttributes)
+
+	return t
 }
 
 // Span is a convenience wrapper around trace.Span with helper methods
 type Span struct {
-	span trace.Span
+	span   trace.Span
+	filter *tags.Filter
+
+	// Span name and metrics client for SpanMetrics (see Metrics)
+	name    string
+	metrics *metrics.Metrics
+	counts  atomic.Pointer[SpanMetrics] // Created by the first Metrics call, which may come from several goroutines
+}
+
+// Metrics returns the span's SpanMetrics, whose counts are set as span attributes
+// and recorded as counters when the span ends
+func (s *Span) Metrics() *SpanMetrics {
+	if counts := s.counts.Load(); counts != nil {
+		return counts
+	}
+	s.counts.CompareAndSwap(nil, &SpanMetrics{counts: make(map[string]float64)})
+	return s.counts.Load()
 }
 
-// End ends the span
+// End ends the span. SpanMetrics counts are flushed first, so they are part of the exported span
+// and of the MCAP timeline interval.
 func (s *Span) End() {
+	if counts := s.counts.Load(); counts != nil {
+		counts.flush(s.span, s.name, s.metrics)
+	}
 	s.span.End()
 }
 
@@ -48,32 +102,187 @@ func (s *Span) SetError(err error) {
 	}
 }
 
+// RecordPanic records a recovered panic as an exception event with its stack trace
+// and sets the span status to error
+func (s *Span) RecordPanic(value interface{}, stack []byte) {
+	msg := fmt.Sprintf("panic: %v", value)
+	s.span.AddEvent("exception", trace.WithAttributes(
+		attribute.String("exception.type", "panic"),
+		attribute.String("exception.message", msg),
+		attribute.String("exception.stacktrace", string(stack)),
+	))
+	s.span.SetStatus(codes.Error, msg)
+}
+
+// SetCancelled marks the span as cancelled (context.Canceled or context.DeadlineExceeded).
+// The status is left unset so cancellations are not counted as errors.
+func (s *Span) SetCancelled(err error) {
+	attrs := []attribute.KeyValue{attribute.Bool("cancelled", true)}
+	if err != nil {
+		attrs = append(attrs, attribute.String("cancel.reason", err.Error()))
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// setResult sets the span status from the result of a traced function.
+// Context cancellations are recorded with SetCancelled instead of as errors.
+func (s *Span) setResult(err error) {
+	switch {
+	case err == nil:
+		s.SetOK()
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		s.SetCancelled(err)
+	default:
+		s.SetError(err)
+	}
+}
+
 // SetOK sets the span status to OK
 func (s *Span) SetOK() {
 	s.span.SetStatus(codes.Ok, "")
 }
 
+// SpanContext returns the span's trace and span IDs (invalid for a non-recording span without a parent)
+func (s *Span) SpanContext() trace.SpanContext {
+	return s.span.SpanContext()
+}
+
+// IsSampled reports whether the span is sampled and recording, i.e. its attributes will be exported.
+// Use it to skip building expensive attributes for spans dropped by the sampler. Ignored spans
+// (IgnoreSpanNames) carry their parent's span context but record nothing, so they report false.
+//
+//	if span.IsSampled() {
+//	    span.SetAttributesFromStruct(buildDebugInfo())
+//	}
+func (s *Span) IsSampled() bool {
+	return s.span.IsRecording() && s.span.SpanContext().IsSampled()
+}
+
 // AddEvent adds an event to the span
 func (s *Span) AddEvent(name string) {
 	s.span.AddEvent(name)
 }
 
+// AddEventAt adds an event with attributes and an explicit timestamp to the span, for
+// rebuilding traces from recorded data (e.g., replaying an MCAP recording)
+func (s *Span) AddEventAt(name string, t time.Time, attrs map[string]interface{}) {
+	attributes := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		if s.filter.Allow(k) {
+			attributes = append(attributes, convertToAttribute(k, v))
+		}
+	}
+	s.span.AddEvent(name, trace.WithTimestamp(t), trace.WithAttributes(attributes...))
+}
+
+// Timed measures a sub-operation inside the span. It adds an "<eventName>.start" event, runs fn,
+// then adds an "<eventName>.end" event with a duration_ms attribute.
+func (s *Span) Timed(eventName string, fn func()) {
+	start := time.Now()
+	s.span.AddEvent(eventName+".start", trace.WithTimestamp(start))
+
+	fn()
+
+	end := time.Now()
+	durationMs := float64(end.Sub(start)) / float64(time.Millisecond)
+	s.span.AddEvent(eventName+".end",
+		trace.WithTimestamp(end),
+		trace.WithAttributes(attribute.Float64("duration_ms", durationMs)),
+	)
+}
+
 // SetAttribute sets a single attribute on the span
 func (s *Span) SetAttribute(key string, value interface{}) {
+	if !s.filter.Allow(key) {
+		return
+	}
 	s.span.SetAttributes(convertToAttribute(key, value))
 }
 
+// SetAttributeIf sets a single attribute on the span only if cond is true
+func (s *Span) SetAttributeIf(cond bool, key string, value interface{}) {
+	if cond {
+		s.SetAttribute(key, value)
+	}
+}
+
+// SetAttributeNonZero sets a single attribute on the span unless value is nil or
+// the zero value of its type (e.g., "", 0, false, empty slice)
+func (s *Span) SetAttributeNonZero(key string, value interface{}) {
+	if value == nil {
+		return
+	}
+	rv := reflect.ValueOf(value)
+	if rv.IsZero() || (rv.Kind() == reflect.Slice && rv.Len() == 0) {
+		return
+	}
+	s.SetAttribute(key, value)
+}
+
 // SetAttributes sets multiple attributes on the span
 func (s *Span) SetAttributes(attrs map[string]interface{}) {
 	attributes := make([]attribute.KeyValue, 0, len(attrs))
 	for k, v := range attrs {
-		attributes = append(attributes, convertToAttribute(k, v))
+		if s.filter.Allow(k) {
+			attributes = append(attributes, convertToAttribute(k, v))
+		}
 	}
 	s.span.SetAttributes(attributes...)
 }
 
+// SetAttributesFromStruct extracts attributes from a struct using the `pulse:"trace:attribute.name"` tag
+// and sets them on the span. Useful for attributes only known after the work is done (e.g., result sizes).
+func (s *Span) SetAttributesFromStruct(v any) {
+	attrs := filterAttributes(s.filter, extractAttributes(v))
+	if len(attrs) > 0 {
+		s.span.SetAttributes(attrs...)
+	}
+}
+
+// WithContext returns a new Tracing bound to the specified context, for symmetry with
+// Logger.WithContext and Metrics.WithContext. Use CurrentSpan to access the span of the bound context.
+func (t *Tracing) WithContext(ctx context.Context) *Tracing {
+	return &Tracing{
+		tracer:   t.tracer,
+		mcap:     t.mcap,
+		opts:     t.opts,
+		service:  t.service,
+		filter:   t.filter,
+		defaults: t.defaults,
+		ctx:      ctx,
+		metrics:  t.metrics,
+		recorder: t.recorder,
+	}
+}
+
+// CurrentSpan returns the span of the context bound with WithContext.
+// If the context has no span, a no-op span is returned.
+func (t *Tracing) CurrentSpan() *Span {
+	return t.SpanFromContext(t.ctx)
+}
+
+// SpanFromContext returns the current span from the context.
+// If the context has no span, a no-op span is returned.
+func (t *Tracing) SpanFromContext(ctx context.Context) *Span {
+	return &Span{span: trace.SpanFromContext(ctx), filter: t.filter}
+}
+
+// AddEvent adds an event to the span in ctx, for helpers that have the context but not the *Span.
+// Does nothing if ctx has no recording span.
+func (t *Tracing) AddEvent(ctx context.Context, name string) {
+	t.SpanFromContext(ctx).AddEvent(name)
+}
+
+// SetAttribute sets an attribute on the span in ctx, for helpers that have the context but not the *Span.
+// The attribute allow/deny policy applies as for Span.SetAttribute. Does nothing if ctx has no recording span.
+func (t *Tracing) SetAttribute(ctx context.Context, key string, value interface{}) {
+	t.SpanFromContext(ctx).SetAttribute(key, value)
+}
+
 // Start creates a new span with the given name and automatically extracts attributes from the provided struct
 // using the `pulse:"trace:attribute.name"` tag. Returns a new context with the span and the span itself.
+// Use Begin for spans without data and StartWithAttrs for attributes from a map; all three add the
+// default attributes and honor IgnoreSpanNames.
 //
 // Example usage:
 //
@@ -86,42 +295,133 @@ func (s *Span) SetAttributes(attrs map[string]interface{}) {
 //	ctx, span := tracing.Start(ctx, "ProcessRequest", Request{UserID: "123", Action: "login"})
 //	defer span.End()
 func (t *Tracing) Start(ctx context.Context, spanName string, data ...interface{}) (context.Context, *Span) {
-	if !t.opts.Enabled {
+	var primary interface{}
+	if len(data) > 0 {
+		primary = data[0]
+	}
+	return t.start(ctx, spanName, primary, TraceOptions{})
+}
+
+// start implements Start and TraceWith: data is the tagged struct (or nil), opts the extra start options
+func (t *Tracing) start(ctx context.Context, spanName string, data interface{}, opts TraceOptions) (context.Context, *Span) {
+	// Checked before the enabled check, so missing tags are reported even without a tracing pipeline
+	if t.opts.WarnUntagged && data != nil {
+		warnUntagged(data)
+	}
+
+	if !t.opts.Enabled || t.tracer == nil {
 		// Return a no-op span if tracing is disabled
 		return ctx, &Span{span: trace.SpanFromContext(ctx)}
 	}
+	spanName = t.spanName(spanName)
+	if t.ignored(spanName) {
+		return startNonRecording(ctx)
+	}
 
-	// Start the span
-	newCtx, otelSpan := t.tracer.Start(ctx, spanName)
+	// Default attributes come first so struct attributes with the same key override them
+	attrs := append([]attribute.KeyValue(nil), t.defaults...)
 
 	// Extract attributes from data structs using tags
-	if len(data) > 0 {
-		attrs := extractAttributes(data[0])
-		if len(attrs) > 0 {
-			otelSpan.SetAttributes(attrs...)
+	if data != nil {
+		attrs = append(attrs, filterAttributes(t.filter, extractAttributes(data))...)
+	}
+	for k, v := range opts.Attributes {
+		if t.filter.Allow(k) {
+			attrs = append(attrs, convertToAttribute(k, v))
 		}
 	}
+	if t.opts.RecordCaller {
+		attrs = append(attrs, filterAttributes(t.filter, callerAttributes())...)
+	}
+
+	var startOpts []trace.SpanStartOption
+	if len(attrs) > 0 {
+		// Pass attributes at start so samplers can see them
+		startOpts = append(startOpts, trace.WithAttributes(attrs...))
+	}
+	startOpts = append(startOpts, opts.startOptions()...)
+
+	// Start the span
+	newCtx, otelSpan := t.tracer.Start(ctx, spanName, startOpts...)
+
+	return newCtx, &Span{span: otelSpan, filter: t.filter, name: spanName, metrics: t.metrics}
+}
+
+// Begin creates a new span with no data, only the default attributes.
+// It is the same as Start(ctx, spanName) and StartWithAttrs(ctx, spanName, nil).
+//
+//	ctx, span := tracing.Begin(ctx, "Flush")
+//	defer span.End()
+func (t *Tracing) Begin(ctx context.Context, spanName string) (context.Context, *Span) {
+	return t.Start(ctx, spanName)
+}
+
+// StartFromCarrier creates a new span continuing the trace serialized in carrier
+// (e.g., message headers written by a producer with the W3C traceparent format).
+// The parent span context is extracted with the global propagator; if the carrier
+// holds no trace context, the span is a child of the span in ctx as with Start.
+func (t *Tracing) StartFromCarrier(ctx context.Context, spanName string, carrier map[string]string, data ...interface{}) (context.Context, *Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+	return t.Start(ctx, spanName, data...)
+}
+
+// ignored reports whether spans with this name are suppressed by TracingOptions.IgnoreSpanNames
+func (t *Tracing) ignored(spanName string) bool {
+	for _, pattern := range t.opts.IgnoreSpanNames {
+		if pattern == spanName {
+			return true
+		}
+		if ok, err := path.Match(pattern, spanName); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
 
-	return newCtx, &Span{span: otelSpan}
+// startNonRecording returns a span that records nothing in place of an ignored span.
+// It carries the parent span context, so child spans still join the caller's trace.
+func startNonRecording(ctx context.Context) (context.Context, *Span) {
+	newCtx := trace.ContextWithSpanContext(ctx, trace.SpanContextFromContext(ctx))
+	return newCtx, &Span{span: trace.SpanFromContext(newCtx)}
 }
 
-// StartWithAttrs creates a new span with explicit attributes (no struct tag parsing)
+// StartWithAttrs creates a new span with explicit attributes (no struct tag parsing).
+// Use it when attributes are only known at runtime; prefer Start with a tagged struct otherwise.
 func (t *Tracing) StartWithAttrs(ctx context.Context, spanName string, attrs map[string]interface{}) (context.Context, *Span) {
-	if !t.opts.Enabled {
+	return t.startWithAttrs(ctx, spanName, attrs, t.opts.RecordCaller)
+}
+
+// startWithAttrs implements StartWithAttrs. recordCaller adds the caller's code.* attributes;
+// HTTPMiddleware leaves them out since its caller is net/http.
+func (t *Tracing) startWithAttrs(ctx context.Context, spanName string, attrs map[string]interface{}, recordCaller bool) (context.Context, *Span) {
+	if !t.opts.Enabled || t.tracer == nil {
 		return ctx, &Span{span: trace.SpanFromContext(ctx)}
 	}
+	spanName = t.spanName(spanName)
+	if t.ignored(spanName) {
+		return startNonRecording(ctx)
+	}
 
-	newCtx, otelSpan := t.tracer.Start(ctx, spanName)
-
-	if len(attrs) > 0 {
-		attributes := make([]attribute.KeyValue, 0, len(attrs))
-		for k, v := range attrs {
+	// Default attributes come first so explicit attributes with the same key override them
+	attributes := append([]attribute.KeyValue(nil), t.defaults...)
+	for k, v := range attrs {
+		if t.filter.Allow(k) {
 			attributes = append(attributes, convertToAttribute(k, v))
 		}
-		otelSpan.SetAttributes(attributes...)
+	}
+	if recordCaller {
+		attributes = append(attributes, filterAttributes(t.filter, callerAttributes())...)
+	}
+
+	var startOpts []trace.SpanStartOption
+	if len(attributes) > 0 {
+		// Pass attributes at start so samplers can see them
+		startOpts = append(startOpts, trace.WithAttributes(attributes...))
 	}
 
-	return newCtx, &Span{span: otelSpan}
+	newCtx, otelSpan := t.tracer.Start(ctx, spanName, startOpts...)
+
+	return newCtx, &Span{span: otelSpan, filter: t.filter, name: spanName, metrics: t.metrics}
 }
 
 // Trace is a convenience function that wraps a function with a span
@@ -138,26 +438,37 @@ func (t *Tracing) Trace(ctx context.Context, spanName string, data interface{},
 	defer span.End()
 
 	err := fn(ctx, span)
-	if err != nil {
-		span.SetError(err)
-	} else {
-		span.SetOK()
-	}
+	span.setResult(err)
+
+	return err
+}
+
+// TraceWith is Trace with span start options (kind, links, extra attributes, start time) for the
+// cases Trace does not cover, e.g. a consumer span linked to the producer spans of a batch:
+//
+//	err := tracing.TraceWith(ctx, "ProcessBatch", tracing.TraceOptions{
+//	    Kind:  trace.SpanKindConsumer,
+//	    Links: links,
+//	}, batch, func(ctx context.Context, span *Span) error {
+//	    return process(ctx, batch)
+//	})
+func (t *Tracing) TraceWith(ctx context.Context, spanName string, opts TraceOptions, data interface{}, fn func(context.Context, *Span) error) error {
+	ctx, span := t.start(ctx, spanName, data, opts)
+	defer span.End()
+
+	err := fn(ctx, span)
+	span.setResult(err)
 
 	return err
 }
 
 // TraceFunc is a convenience function that wraps a function with a span (no data struct)
 func (t *Tracing) TraceFunc(ctx context.Context, spanName string, fn func(context.Context, *Span) error) error {
-	ctx, span := t.StartWithAttrs(ctx, spanName, nil)
+	ctx, span := t.Begin(ctx, spanName)
 	defer span.End()
 
 	err := fn(ctx, span)
-	if err != nil {
-		span.SetError(err)
-	} else {
-		span.SetOK()
-	}
+	span.setResult(err)
 
 	return err
 }
@@ -169,7 +480,6 @@ func extractAttributes(data interface{}) []attribute.KeyValue {
 	}
 
 	v := reflect.ValueOf(data)
-	t := reflect.TypeOf(data)
 
 	// Handle pointers
 	if v.Kind() == reflect.Ptr {
@@ -177,7 +487,6 @@ func extractAttributes(data interface{}) []attribute.KeyValue {
 			return nil
 		}
 		v = v.Elem()
-		t = t.Elem()
 	}
 
 	// Only process structs
@@ -185,28 +494,101 @@ func extractAttributes(data interface{}) []attribute.KeyValue {
 		return nil
 	}
 
-	attrs := make([]attribute.KeyValue, 0)
+	return structAttributes(v, make([]attribute.KeyValue, 0))
+}
+
+// warnUntagged reports a data struct without trace tags (TracingOptions.WarnUntagged)
+func warnUntagged(data interface{}) {
+	rt := reflect.TypeOf(data)
+	if rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt != nil && rt.Kind() == reflect.Struct && !hasTraceTags(rt) {
+		tags.WarnUntagged("Tracing.Start", tags.KindTrace, rt)
+	}
+}
 
+// hasTraceTags reports whether a struct type, or a struct it embeds, has a `pulse:"trace:..."` tag
+func hasTraceTags(rt reflect.Type) bool {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && field.Tag.Get(tags.Name) == "" && ft.Kind() == reflect.Struct {
+			if hasTraceTags(ft) {
+				return true
+			}
+			continue
+		}
+
+		if tag, ok, _ := tags.Lookup(field); ok && tag.Kind == tags.KindTrace {
+			return true
+		}
+	}
+	return false
+}
+
+// structAttributes appends the `pulse:"trace:..."` attributes of a struct value to attrs,
+// descending into embedded structs (e.g., a BaseRequest embedded in every request type)
+func structAttributes(v reflect.Value, attrs []attribute.KeyValue) []attribute.KeyValue {
+	t := v.Type()
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
 		value := v.Field(i)
 
-		// Get the pulse tag and check if it starts with "trace:"
-		tag := field.Tag.Get("pulse")
-		if tag == "" {
+		if embedded, ok := tags.Embedded(field, value); ok {
+			attrs = structAttributes(embedded, attrs)
+			continue
+		}
+
+		// Parse the tag format: "trace:attribute.name" (malformed tags are skipped, see tags.ValidateStruct)
+		tag, ok, err := tags.Lookup(field)
+		if !ok || err != nil || tag.Kind != tags.KindTrace {
 			continue
 		}
 
-		// Parse the tag format: "trace:attribute.name"
-		if len(tag) > 6 && tag[:6] == "trace:" {
-			attrName := tag[6:] // Extract attribute name after "trace:"
+		// Convert field value to attribute
+		attr := convertToAttribute(tag.Name, value.Interface())
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
+// filterAttributes drops attributes whose keys are not allowed by the filter
+func filterAttributes(filter *tags.Filter, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if filter == nil {
+		return attrs
+	}
+	filtered := attrs[:0]
+	for _, attr := range attrs {
+		if filter.Allow(string(attr.Key)) {
+			filtered = append(filtered, attr)
+		}
+	}
+	return filtered
+}
 
-			// Convert field value to attribute
-			attr := convertToAttribute(attrName, value.Interface())
-			attrs = append(attrs, attr)
+// defaultAttributes converts configured default attributes to span attributes, sorted by key
+func defaultAttributes(filter *tags.Filter, defaults map[string]interface{}) []attribute.KeyValue {
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		if filter.Allow(k) {
+			keys = append(keys, k)
 		}
 	}
+	sort.Strings(keys)
 
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, convertToAttribute(k, defaults[k]))
+	}
 	return attrs
 }
 
@@ -233,6 +615,21 @@ func convertToAttribute(key string, value interface{}) attribute.KeyValue {
 		return attribute.Float64Slice(key, v)
 	case []bool:
 		return attribute.BoolSlice(key, v)
+	case encoding.TextMarshaler:
+		// Canonical text form of IDs and addresses (uuid.UUID, net.IP, time.Time), which are
+		// byte arrays or structs underneath
+		if b, err := v.MarshalText(); err == nil {
+			return attribute.String(key, string(b))
+		}
+		return attribute.String(key, fmt.Sprint(value))
+	case fmt.Stringer:
+		// Custom types (IDs, enums) usually implement Stringer
+		return attribute.String(key, v.String())
+	case json.Marshaler:
+		if b, err := v.MarshalJSON(); err == nil {
+			return attribute.String(key, string(b))
+		}
+		return attribute.String(key, reflect.ValueOf(value).String())
 	default:
 		// For unsupported types, convert to string
 		return attribute.String(key, reflect.ValueOf(value).String())
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/internal/telemetry"
+	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestTracing returns a Tracing whose ended spans are kept by the returned recorder
+func newTestTracing(t *testing.T) (*Tracing, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	tracer := telemetry.NewTracer(provider.Tracer("test"))
+	return NewTracing(options.ServiceOptions{Name: "test"}, options.TracingOptions{Enabled: true}, nil, tracer, nil, recorder), recorder
+}
+
+type BaseRequest struct {
+	RequestID string `pulse:"trace:request.id"`
+	Internal  string // Not tagged, not extracted
+}
+
+type Meta struct {
+	Tenant string `pulse:"trace:tenant.id"`
+}
+
+type createOrderRequest struct {
+	BaseRequest
+	*Meta
+	OrderID string `pulse:"trace:order.id"`
+}
+
+func TestExtractAttributesEmbedded(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want map[attribute.Key]string
+	}{
+		{
+			name: "embedded struct and pointer",
+			data: createOrderRequest{
+				BaseRequest: BaseRequest{RequestID: "req-1", Internal: "x"},
+				Meta:        &Meta{Tenant: "acme"},
+				OrderID:     "42",
+			},
+			want: map[attribute.Key]string{"request.id": "req-1", "tenant.id": "acme", "order.id": "42"},
+		},
+		{
+			name: "nil embedded pointer",
+			data: &createOrderRequest{BaseRequest: BaseRequest{RequestID: "req-2"}, OrderID: "43"},
+			want: map[attribute.Key]string{"request.id": "req-2", "order.id": "43"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[attribute.Key]string)
+			for _, kv := range extractAttributes(tt.data) {
+				got[kv.Key] = kv.Value.Emit()
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("extractAttributes() = %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpanMetricsConcurrent(t *testing.T) {
+	tracing, recorder := newTestTracing(t)
+	_, span := tracing.Begin(context.Background(), "Batch")
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			span.Metrics().Inc("items")
+		}()
+	}
+	wg.Wait()
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("got %d ended spans, want 1", len(ended))
+	}
+	for _, kv := range ended[0].Attributes() {
+		if kv.Key == "items" {
+			if got := kv.Value.AsFloat64(); got != workers {
+				t.Errorf("items = %v, want %d", got, workers)
+			}
+			return
+		}
+	}
+	t.Errorf("span has no items attribute")
+}
@@ -0,0 +1,153 @@
+package pulse
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/machanirobotics/pulse/go/internal/foxglove"
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+// mcapWriters holds the MCAP writer of each signal. Signals may share a writer or have none.
+type mcapWriters struct {
+	logs    *foxglove.UnifiedMcapWriter
+	metrics *foxglove.UnifiedMcapWriter
+	traces  *foxglove.UnifiedMcapWriter
+	spatial *foxglove.UnifiedMcapWriter
+
+	// Every distinct writer, closed (or released) with Pulse
+	all []*foxglove.UnifiedMcapWriter
+}
+
+// newMcapWriters opens the MCAP writers configured in the Foxglove options: a shared writer,
+// one file per FoxgloveOptions.Outputs entry, or a single file at McapPath for every signal.
+func newMcapWriters(serviceOpts options.ServiceOptions, opts options.FoxgloveOptions) (*mcapWriters, error) {
+	w := &mcapWriters{}
+	if !opts.Enabled {
+		return w, nil
+	}
+
+	switch {
+	case opts.SharedWriter != nil:
+		shared, ok := opts.SharedWriter.(*foxglove.UnifiedMcapWriter)
+		if !ok {
+			return nil, fmt.Errorf("unsupported shared MCAP writer %T, use pulse.NewSharedMcapWriter", opts.SharedWriter)
+		}
+		if err := shared.Acquire(); err != nil {
+			return nil, err
+		}
+		w.route(shared, options.McapSignalLogs, options.McapSignalMetrics, options.McapSignalTraces, options.McapSignalSpatial)
+
+	case len(opts.Outputs) > 0:
+		for i, output := range opts.Outputs {
+			outputOpts := opts
+			outputOpts.McapPath = output.Path
+			if i > 0 {
+				outputOpts.LiveStream.Enabled = false // Only one live stream server per address
+			}
+
+			writer, err := w.open(serviceOpts, outputOpts, output.Signals)
+			if err != nil {
+				w.close()
+				return nil, fmt.Errorf("MCAP output %q: %w", output.Path, err)
+			}
+			w.route(writer, output.Signals...)
+		}
+
+	case opts.McapPath != "":
+		writer, err := foxglove.NewUnifiedMcapWriter(serviceOpts, opts)
+		if err != nil {
+			return nil, err
+		}
+		w.route(writer, options.McapSignalLogs, options.McapSignalMetrics, options.McapSignalTraces, options.McapSignalSpatial)
+	}
+
+	return w, nil
+}
+
+// writeResource writes the service resource attributes to every writer as a "resource" metadata
+// record, so a recording opened later shows which service, version and environment produced it
+func (w *mcapWriters) writeResource(attrs map[string]string) error {
+	for _, writer := range w.all {
+		if err := writer.WriteMetadata("resource", attrs); err != nil {
+			return fmt.Errorf("failed to write MCAP resource metadata to %s: %w", writer.GetFilePath(), err)
+		}
+	}
+	return nil
+}
+
+// open checks the signals of an output and creates its writer
+func (w *mcapWriters) open(serviceOpts options.ServiceOptions, opts options.FoxgloveOptions, signals []options.McapSignal) (*foxglove.UnifiedMcapWriter, error) {
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("no signals configured")
+	}
+	for _, signal := range signals {
+		target := w.signal(signal)
+		if target == nil {
+			return nil, fmt.Errorf("unknown signal %q", signal)
+		}
+		if *target != nil {
+			return nil, fmt.Errorf("signal %q is already routed to %s", signal, (*target).GetFilePath())
+		}
+	}
+	return foxglove.NewUnifiedMcapWriter(serviceOpts, opts)
+}
+
+// route assigns a writer to the given signals
+func (w *mcapWriters) route(writer *foxglove.UnifiedMcapWriter, signals ...options.McapSignal) {
+	for _, signal := range signals {
+		*w.signal(signal) = writer
+	}
+	w.all = append(w.all, writer)
+}
+
+// signal returns the writer field of a signal, or nil for unknown signals
+func (w *mcapWriters) signal(signal options.McapSignal) **foxglove.UnifiedMcapWriter {
+	switch signal {
+	case options.McapSignalLogs:
+		return &w.logs
+	case options.McapSignalMetrics:
+		return &w.metrics
+	case options.McapSignalTraces:
+		return &w.traces
+	case options.McapSignalSpatial:
+		return &w.spatial
+	default:
+		return nil
+	}
+}
+
+// close closes every writer, used when exiting on a fatal log
+func (w *mcapWriters) close() {
+	for _, writer := range w.all {
+		_ = writer.Close() // Ignore error, exiting anyway
+	}
+}
+
+// release releases every writer; shared writers are only closed once their last user releases them
+func (w *mcapWriters) release() {
+	for _, writer := range w.all {
+		_ = writer.Release() // Ignore error during shutdown
+	}
+}
+
+// rotate rotates every writer (see UnifiedMcapWriter.Rotate)
+func (w *mcapWriters) rotate() error {
+	var errs []error
+	for _, writer := range w.all {
+		if err := writer.Rotate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RotateRecording closes the current MCAP recording and continues in a new, timestamped file (per
+// output with FoxgloveOptions.Outputs), e.g. to keep each mission of a robot in its own file.
+// A shared writer is rotated for every Pulse using it. Returns an error if MCAP recording is disabled.
+func (p *Pulse) RotateRecording() error {
+	if p.mcap == nil || len(p.mcap.all) == 0 {
+		return errors.New("MCAP recording is not enabled")
+	}
+	return p.mcap.rotate()
+}
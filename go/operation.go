@@ -0,0 +1,82 @@
+package pulse
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Operation statuses set as the operation.status attribute by Pulse.Operation
+const (
+	OperationOK        = "ok"
+	OperationError     = "error"
+	OperationCancelled = "cancelled" // context.Canceled or context.DeadlineExceeded, not counted as an error
+)
+
+// operationLog is logged by Operation when an operation starts and ends
+type operationLog struct {
+	Operation  string  `json:"operation" pulse:"attribute:operation"`
+	Status     string  `json:"status,omitempty" pulse:"attribute:operation.status"`
+	DurationMs float64 `json:"duration_ms,omitempty" pulse:"attribute:duration_ms"`
+	Error      string  `json:"error,omitempty" pulse:"attribute:error"`
+	ErrorType  string  `json:"error_type,omitempty" pulse:"attribute:error.type"`
+}
+
+// operationMetric is recorded by Operation for every finished operation
+type operationMetric struct {
+	Count      int     `pulse:"metric:counter:operations.total"`
+	DurationMs float64 `pulse:"metric:histogram:operation.duration_ms"`
+	Operation  string  `pulse:"attribute:operation"`
+	Status     string  `pulse:"attribute:operation.status"`
+}
+
+// Operation runs fn as one fully observed operation:
+// it starts a span named name with the trace attributes of data (like Tracing.Trace), logs the start
+// at debug level and the end at info level (warn if cancelled, error if fn fails) with trace correlation,
+// records the duration in the operation.duration_ms histogram and counts the operation in operations.total
+// (both with operation and operation.status attributes), and records a returned error on the span.
+// The operation name should be a fixed string, as it is a metric attribute. Returns the error of fn.
+func (p *Pulse) Operation(ctx context.Context, name string, data interface{}, fn func(context.Context, *Span) error) error {
+	return p.Tracing.Trace(ctx, name, data, func(ctx context.Context, span *Span) error {
+		logger := p.Logger.WithContext(ctx)
+		logger.Debug("Operation started", operationLog{Operation: name}, data)
+
+		start := time.Now()
+		err := fn(ctx, span)
+		durationMs := float64(time.Since(start)) / float64(time.Millisecond)
+
+		entry := operationLog{Operation: name, Status: operationStatus(err), DurationMs: durationMs}
+		switch entry.Status {
+		case OperationOK:
+			logger.Info("Operation completed", entry)
+		case OperationCancelled:
+			entry.Error = err.Error()
+			logger.Warn("Operation cancelled", entry)
+		default:
+			entry.Error, entry.ErrorType = err.Error(), errorType(err)
+			logger.Error("Operation failed", entry)
+		}
+
+		// Ignore error, reporting must not fail the caller
+		_ = p.Metrics.RecordCtx(ctx, operationMetric{
+			Count:      1,
+			DurationMs: durationMs,
+			Operation:  name,
+			Status:     entry.Status,
+		})
+
+		return err
+	})
+}
+
+// operationStatus returns the operation.status of a result, matching how the span status is set
+func operationStatus(err error) string {
+	switch {
+	case err == nil:
+		return OperationOK
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return OperationCancelled
+	default:
+		return OperationError
+	}
+}
@@ -0,0 +1,9 @@
+package options
+
+// AttributeFilterOptions defines which attribute keys may be emitted.
+// Patterns use path.Match glob syntax (e.g., "user.*", "internal_*").
+// If Allowed is empty, all keys are allowed unless denied. Denied wins on conflict.
+type AttributeFilterOptions struct {
+	Allowed []string `json:"allowed"` // Allowed attribute key patterns (empty allows all)
+	Denied  []string `json:"denied"`  // Denied attribute key patterns
+}
@@ -5,15 +5,49 @@ import (
 	"strconv"
 )
 
-// Default returns default Pulse options with all features enabled and configured for local development
+// Default returns default Pulse options with all features enabled and configured for local development.
+// The environment is read from PULSE_ENVIRONMENT (default: development), see DefaultForEnvironment.
+// Prefer DefaultForEnvironment(serviceOpts.Environment), so the defaults match ServiceOptions.Environment.
 func Default() PulseOptions {
-	return PulseOptions{
+	return DefaultForEnvironment(environmentFromEnv())
+}
+
+// DefaultForEnvironment returns default Pulse options for the given environment.
+// On Jetson, edge-friendly defaults are applied: longer metric export intervals, lz4 MCAP
+// compression, only CPU, goroutine and in-use space profiling (ProfilingOptions.LowOverhead), and MCAP recording enabled as a local
+// fallback for intermittent connectivity, flushed to disk every 5 seconds. Every value can still be overridden by environment
+// variables or by modifying the returned options.
+func DefaultForEnvironment(env Environment) PulseOptions {
+	opts := builtinDefaults(env)
+	applyEnv(&opts)
+	return opts
+}
+
+// DefaultTelemetry returns default telemetry options with all features enabled
+// and configured for local development (stdout exporters).
+// The environment is read from PULSE_ENVIRONMENT (default: development), see DefaultTelemetryForEnvironment,
+// which is preferred with ServiceOptions.Environment.
+func DefaultTelemetry() TelemetryOptions {
+	return DefaultTelemetryForEnvironment(environmentFromEnv())
+}
+
+// DefaultTelemetryForEnvironment returns default telemetry options for the given environment.
+// On Jetson, metrics are exported less often (every 60 seconds, PULSE_METRICS_EXPORT_INTERVAL overrides)
+// to save bandwidth. In production, 10% of traces are sampled (PULSE_TRACES_SAMPLE_RATIO overrides);
+// other environments sample every span. The standard OTel variables OTEL_SDK_DISABLED, OTEL_{TRACES,METRICS,LOGS}_EXPORTER=none,
+// OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_COMPRESSION and OTEL_METRIC_EXPORT_INTERVAL are honored too.
+func DefaultTelemetryForEnvironment(env Environment) TelemetryOptions {
+	opts := builtinTelemetryDefaults(env)
+	applyTelemetryEnv(&opts)
+	return opts
+}
+
+// builtinDefaults returns the default Pulse options for the environment, before environment variables
+func builtinDefaults(env Environment) PulseOptions {
+	opts := PulseOptions{
 		Profiling: ProfilingOptions{
-			Enabled:              getBoolFromEnvOrDefault("PULSE_PROFILING_ENABLED", false),
-			ServerAddress:        getFromEnvOrDefault("PULSE_PROFILING_SERVER", "http://localhost:4040"),
-			BasicAuthUser:        getFromEnvOrDefault("PULSE_PROFILING_USER", ""),
-			BasicAuthPassword:    getFromEnvOrDefault("PULSE_PROFILING_PASSWORD", ""),
-			TenantID:             getFromEnvOrDefault("PULSE_PROFILING_TENANT_ID", ""),
+			Enabled:              false,
+			ServerAddress:        "http://localhost:4040",
 			ProfileCPU:           true,
 			ProfileAllocObjects:  true,
 			ProfileAllocSpace:    true,
@@ -35,36 +69,150 @@ func Default() PulseOptions {
 				CallerOffset:    1,
 			},
 		},
-		Foxglove: FoxgloveOptions{
-			Enabled:  getBoolFromEnvOrDefault("FOXGLOVE_MCAP_ENABLED", false),
-			McapPath: getFromEnvOrDefault("FOXGLOVE_MCAP_PATH", ""),
+		Tracing: TracingOptions{
+			Enabled:      true, // Matches Telemetry.Tracing.Enabled
+			WarnUntagged: env == Development,
 		},
-		Telemetry: DefaultTelemetry(),
+		Telemetry: builtinTelemetryDefaults(env),
+	}
+
+	if env == Jetson {
+		// Allocation and in-use object profiles are expensive on limited CPUs
+		opts.Profiling.ProfileAllocObjects = false
+		opts.Profiling.ProfileAllocSpace = false
+		opts.Profiling.ProfileInuseObjects = false
+		opts.Profiling.LowOverhead = true
+
+		// Record locally so nothing is lost while the device is offline
+		opts.Foxglove.Enabled = true
+		opts.Foxglove.McapPath = "logs/pulse.mcap"
+		opts.Foxglove.Compression = McapCompressionLZ4
+		opts.Foxglove.FlushIntervalSeconds = 5 // Robots may lose power at any time
 	}
+
+	return opts
 }
 
-// DefaultTelemetry returns default telemetry options with all features enabled
-// and configured for local development (stdout exporters)
-func DefaultTelemetry() TelemetryOptions {
+// builtinTelemetryDefaults returns the default telemetry options for the environment, before environment variables
+func builtinTelemetryDefaults(env Environment) TelemetryOptions {
+	exportInterval := 10
+	if env == Jetson {
+		exportInterval = 60
+	}
+
+	var sampleRatio *float64 // Sample every span outside production
+	if env == Production {
+		sampleRatio = Ratio(0.1)
+	}
+
 	return TelemetryOptions{
 		Logging: LoggingTelemetryOptions{
 			Enabled: true,
 		},
 		Metrics: MetricsTelemetryOptions{
 			Enabled:               true,
-			ExportIntervalSeconds: 10,
+			ExportIntervalSeconds: exportInterval,
+			WarnUntagged:          env == Development,
 		},
 		Tracing: TracingTelemetryOptions{
-			Enabled: true,
+			Enabled:     true,
+			SampleRatio: sampleRatio,
 		},
 		OTLP: OTLPOptions{
-			Host:    getFromEnvOrDefault("OTEL_EXPORTER_OTLP_HOST", "localhost"),
-			Port:    getIntFromEnvOrDefault("OTEL_EXPORTER_OTLP_PORT", 4317),
-			Enabled: getBoolFromEnvOrDefault("OTEL_EXPORTER_OTLP_ENABLED", false),
+			Port:     4317,
+			FailOpen: true,
+			Retry: OTLPRetryOptions{
+				Enabled: true,
+			},
 		},
+		Resource: ResourceOptions{
+			Host:    true,
+			Process: true,
+		},
+		defaultsFor: env,
+	}
+}
+
+// applyEnv overrides opts with the environment variables that are set (see applyTelemetryEnv)
+func applyEnv(opts *PulseOptions) {
+	setBoolFromEnv(&opts.Profiling.Enabled, "PULSE_PROFILING_ENABLED")
+	setFromEnv(&opts.Profiling.ServerAddress, "PULSE_PROFILING_SERVER")
+	setFromEnv(&opts.Profiling.BasicAuthUser, "PULSE_PROFILING_USER")
+	setFromEnv(&opts.Profiling.BasicAuthPassword, "PULSE_PROFILING_PASSWORD")
+	setFromEnv(&opts.Profiling.TenantID, "PULSE_PROFILING_TENANT_ID")
+	setBoolFromEnv(&opts.Profiling.LowOverhead, "PULSE_PROFILING_LOW_OVERHEAD")
+
+	setBoolFromEnv(&opts.Foxglove.Enabled, "FOXGLOVE_MCAP_ENABLED")
+	setFromEnv(&opts.Foxglove.McapPath, "FOXGLOVE_MCAP_PATH")
+	setIntFromEnv(&opts.Foxglove.FlushIntervalSeconds, "FOXGLOVE_MCAP_FLUSH_INTERVAL")
+
+	applyTelemetryEnv(&opts.Telemetry)
+	if !otelSignalEnabled(envTracesExporter) {
+		opts.Tracing.Enabled = false // Matches Telemetry.Tracing.Enabled
 	}
 }
 
+// applyTelemetryEnv overrides opts with the telemetry environment variables that are set.
+// Standard OTel variables are applied first, so the Pulse-specific ones take precedence.
+func applyTelemetryEnv(opts *TelemetryOptions) {
+	if !otelSignalEnabled(envLogsExporter) {
+		opts.Logging.Enabled = false
+	}
+	if !otelSignalEnabled(envMetricsExporter) {
+		opts.Metrics.Enabled = false
+	}
+	if !otelSignalEnabled(envTracesExporter) {
+		opts.Tracing.Enabled = false
+	}
+
+	opts.Metrics.ExportIntervalSeconds = otelMetricExportIntervalSeconds(opts.Metrics.ExportIntervalSeconds)
+	setIntFromEnv(&opts.Metrics.ExportIntervalSeconds, "PULSE_METRICS_EXPORT_INTERVAL")
+	setRatioFromEnv(&opts.Tracing.SampleRatio, "PULSE_TRACES_SAMPLE_RATIO")
+
+	if host, port, ok := otelEndpointFromEnv(); ok {
+		opts.OTLP.Host, opts.OTLP.Port, opts.OTLP.Enabled = host, port, true
+	}
+	if compression := otelCompressionFromEnv(); compression != "" {
+		opts.OTLP.Compression = compression
+	}
+	setFromEnv(&opts.OTLP.Host, "OTEL_EXPORTER_OTLP_HOST")
+	setIntFromEnv(&opts.OTLP.Port, "OTEL_EXPORTER_OTLP_PORT")
+	setBoolFromEnv(&opts.OTLP.Enabled, "OTEL_EXPORTER_OTLP_ENABLED")
+	setBoolFromEnv(&opts.OTLP.FailOpen, "PULSE_OTLP_FAIL_OPEN")
+	setFromEnv(&opts.OTLP.UnixSocket, "PULSE_OTLP_UNIX_SOCKET")
+	setBoolFromEnv(&opts.OTLP.Retry.Enabled, "PULSE_OTLP_RETRY_ENABLED")
+}
+
+// environmentFromEnv returns the environment set in PULSE_ENVIRONMENT, or development if unset
+func environmentFromEnv() Environment {
+	return Environment(getFromEnvOrDefault("PULSE_ENVIRONMENT", string(Development)))
+}
+
+// setFromEnv sets *dst to the value of the environment variable with the given key, if it is set
+func setFromEnv(dst *string, key string) {
+	*dst = getFromEnvOrDefault(key, *dst)
+}
+
+// setIntFromEnv sets *dst to the value of the environment variable with the given key,
+// if it is set to an integer
+func setIntFromEnv(dst *int, key string) {
+	*dst = getIntFromEnvOrDefault(key, *dst)
+}
+
+// setRatioFromEnv sets *dst to the value of the environment variable with the given key,
+// if it is set to a number (including 0)
+func setRatioFromEnv(dst **float64, key string) {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		*dst = Ratio(value)
+	}
+}
+
+// setBoolFromEnv sets *dst to the value of the environment variable with the given key,
+// if it is set to a boolean
+func setBoolFromEnv(dst *bool, key string) {
+	*dst = getBoolFromEnvOrDefault(key, *dst)
+}
+
 // getFromEnvOrDefault returns the value of the environment variable with the given key,
 // or the default value if the environment variable is not set
 func getFromEnvOrDefault(key string, defaultValue string) string {
@@ -102,3 +250,8 @@ func getBoolFromEnvOrDefault(key string, defaultValue bool) bool {
 	}
 	return boolValue
 }
+
+// Ratio returns a pointer to r, for optional ratios such as TracingTelemetryOptions.SampleRatio
+func Ratio(r float64) *float64 {
+	return &r
+}
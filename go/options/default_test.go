@@ -0,0 +1,30 @@
+package options
+
+import "testing"
+
+func TestDefaultsEnvironment(t *testing.T) {
+	t.Setenv("PULSE_ENVIRONMENT", "")
+
+	for _, env := range []Environment{Development, Staging, Production, Jetson} {
+		if got, ok := DefaultForEnvironment(env).Telemetry.DefaultsEnvironment(); !ok || got != env {
+			t.Errorf("DefaultForEnvironment(%s) defaults are for %q (ok=%v)", env, got, ok)
+		}
+	}
+	if got, _ := Default().Telemetry.DefaultsEnvironment(); got != Development {
+		t.Errorf("Default() defaults are for %q, want development without PULSE_ENVIRONMENT", got)
+	}
+	if _, ok := (TelemetryOptions{}).DefaultsEnvironment(); ok {
+		t.Error("struct literal reports a defaults environment")
+	}
+}
+
+func TestDefaultSampleRatio(t *testing.T) {
+	t.Setenv("PULSE_TRACES_SAMPLE_RATIO", "")
+
+	if r := DefaultForEnvironment(Production).Telemetry.Tracing.SampleRatio; r == nil || *r != 0.1 {
+		t.Errorf("production SampleRatio = %v, want 0.1", r)
+	}
+	if r := DefaultForEnvironment(Development).Telemetry.Tracing.SampleRatio; r != nil {
+		t.Errorf("development SampleRatio = %v, want unset", *r)
+	}
+}
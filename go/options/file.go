@@ -0,0 +1,135 @@
+package options
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFromFile reads Pulse options from a JSON or YAML file (by extension: .json, .yaml or .yml).
+// Keys are the JSON tag names of PulseOptions (e.g., telemetry.otlp.host); unknown keys are errors.
+// Options missing from the file keep the defaults of the PULSE_ENVIRONMENT environment, and the
+// environment variables read by Default override the file. The result is validated, see Validate.
+func LoadFromFile(path string) (PulseOptions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PulseOptions{}, fmt.Errorf("failed to read options file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return PulseOptions{}, fmt.Errorf("failed to parse options file %s: %w", path, err)
+		}
+	default:
+		return PulseOptions{}, fmt.Errorf("unsupported options file extension %q (use .json, .yaml or .yml)", filepath.Ext(path))
+	}
+
+	opts := builtinDefaults(environmentFromEnv())
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields() // Catch typos in keys instead of silently using the default
+	if err := decoder.Decode(&opts); err != nil {
+		return PulseOptions{}, fmt.Errorf("failed to parse options file %s: %w", path, err)
+	}
+
+	applyEnv(&opts)
+
+	if err := opts.Validate(); err != nil {
+		return PulseOptions{}, fmt.Errorf("invalid options in %s: %w", path, err)
+	}
+	return opts, nil
+}
+
+// yamlToJSON converts a YAML document to JSON, so it is decoded with the JSON tags of the options
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return []byte("{}"), nil // Empty file
+	}
+	return json.Marshal(doc)
+}
+
+// Validate checks option values that would otherwise fail or misbehave at runtime:
+// the OTLP port, compression names, MCAP settings and negative intervals or limits.
+// All problems are returned at once.
+func (o PulseOptions) Validate() error {
+	var errs []error
+
+	otlp := o.Telemetry.OTLP
+	if otlp.Enabled && otlp.UnixSocket == "" && (otlp.Port < 1 || otlp.Port > 65535) {
+		errs = append(errs, fmt.Errorf("telemetry.otlp.port %d is out of range", otlp.Port))
+	}
+	for _, e := range []struct {
+		key  string
+		port int
+	}{
+		{"telemetry.tracing.otlp.port", o.Telemetry.Tracing.OTLP.Port},
+		{"telemetry.metrics.otlp.port", o.Telemetry.Metrics.OTLP.Port},
+		{"telemetry.logging.otlp.port", o.Telemetry.Logging.OTLP.Port},
+	} {
+		if e.port < 0 || e.port > 65535 {
+			errs = append(errs, fmt.Errorf("%s %d is out of range", e.key, e.port))
+		}
+	}
+	for _, c := range []struct {
+		key         string
+		compression OTLPCompression
+	}{
+		{"telemetry.otlp.compression", otlp.Compression},
+		{"telemetry.otlp.logCompression", otlp.LogCompression},
+		{"telemetry.otlp.traceCompression", otlp.TraceCompression},
+		{"telemetry.otlp.metricCompression", otlp.MetricCompression},
+	} {
+		switch c.compression {
+		case "", OTLPCompressionNone, OTLPCompressionGzip, OTLPCompressionZstd:
+		default:
+			errs = append(errs, fmt.Errorf("%s %q is not one of none, gzip or zstd", c.key, c.compression))
+		}
+	}
+
+	if o.Telemetry.Metrics.ExportIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("telemetry.metrics.exportIntervalSeconds must not be negative"))
+	}
+	if o.Telemetry.Metrics.CardinalityLimit < 0 {
+		errs = append(errs, fmt.Errorf("telemetry.metrics.cardinalityLimit must not be negative"))
+	}
+	if ratio := o.Telemetry.Tracing.SampleRatio; ratio != nil && (*ratio < 0 || *ratio > 1) {
+		errs = append(errs, fmt.Errorf("telemetry.tracing.sampleRatio %v is not between 0 and 1", *ratio))
+	}
+
+	switch o.Foxglove.Compression {
+	case "", McapCompressionZSTD, McapCompressionLZ4, McapCompressionNone:
+	default:
+		errs = append(errs, fmt.Errorf("foxglove.compression %q is not one of zstd, lz4 or none", o.Foxglove.Compression))
+	}
+	if o.Foxglove.FlushIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("foxglove.flushIntervalSeconds must not be negative"))
+	}
+	switch o.Foxglove.MetricChannelMode {
+	case "", MetricChannelPerMetric, MetricChannelSingle:
+	default:
+		errs = append(errs, fmt.Errorf("foxglove.metricChannelMode %q is not one of per_metric or single", o.Foxglove.MetricChannelMode))
+	}
+	for _, output := range o.Foxglove.Outputs {
+		for _, signal := range output.Signals {
+			switch signal {
+			case McapSignalLogs, McapSignalMetrics, McapSignalTraces, McapSignalSpatial:
+			default:
+				errs = append(errs, fmt.Errorf("foxglove.outputs signal %q is not one of logs, metrics, traces or spatial", signal))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*PulseOptions)
+		wantErr []string // Substrings of the joined error, none if empty
+	}{
+		{name: "defaults", modify: func(*PulseOptions) {}},
+		{name: "port out of range", modify: func(o *PulseOptions) {
+			o.Telemetry.OTLP.Enabled, o.Telemetry.OTLP.Port = true, 70000
+		}, wantErr: []string{"telemetry.otlp.port 70000"}},
+		{name: "port ignored with socket", modify: func(o *PulseOptions) {
+			o.Telemetry.OTLP.Enabled, o.Telemetry.OTLP.Port, o.Telemetry.OTLP.UnixSocket = true, 0, "/run/otel/otlp.sock"
+		}},
+		{name: "port ignored when disabled", modify: func(o *PulseOptions) { o.Telemetry.OTLP.Port = 0 }},
+		{name: "signal port", modify: func(o *PulseOptions) { o.Telemetry.Metrics.OTLP.Port = -1 }, wantErr: []string{"telemetry.metrics.otlp.port -1"}},
+		{name: "compression", modify: func(o *PulseOptions) { o.Telemetry.OTLP.LogCompression = "brotli" }, wantErr: []string{`telemetry.otlp.logCompression "brotli"`}},
+		{name: "negative export interval", modify: func(o *PulseOptions) { o.Telemetry.Metrics.ExportIntervalSeconds = -1 }, wantErr: []string{"exportIntervalSeconds"}},
+		{name: "negative cardinality limit", modify: func(o *PulseOptions) { o.Telemetry.Metrics.CardinalityLimit = -1 }, wantErr: []string{"cardinalityLimit"}},
+		{name: "sample ratio", modify: func(o *PulseOptions) { o.Telemetry.Tracing.SampleRatio = Ratio(1.5) }, wantErr: []string{"sampleRatio 1.5"}},
+		{name: "zero sample ratio", modify: func(o *PulseOptions) { o.Telemetry.Tracing.SampleRatio = Ratio(0) }},
+		{name: "mcap compression", modify: func(o *PulseOptions) { o.Foxglove.Compression = "gzip" }, wantErr: []string{`foxglove.compression "gzip"`}},
+		{name: "negative flush interval", modify: func(o *PulseOptions) { o.Foxglove.FlushIntervalSeconds = -5 }, wantErr: []string{"flushIntervalSeconds"}},
+		{name: "metric channel mode", modify: func(o *PulseOptions) { o.Foxglove.MetricChannelMode = "per_name" }, wantErr: []string{`metricChannelMode "per_name"`}},
+		{name: "output signal", modify: func(o *PulseOptions) {
+			o.Foxglove.Outputs = []McapOutput{{Path: "a.mcap", Signals: []McapSignal{McapSignalLogs, "events"}}}
+		}, wantErr: []string{`signal "events"`}},
+		{name: "all problems at once", modify: func(o *PulseOptions) {
+			o.Telemetry.OTLP.Compression = "lzma"
+			o.Foxglove.FlushIntervalSeconds = -1
+		}, wantErr: []string{`telemetry.otlp.compression "lzma"`, "flushIntervalSeconds"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := builtinDefaults(Development)
+			tt.modify(&opts)
+
+			err := opts.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want errors containing %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+// writeOptionsFile writes content to a file with the given name in a temporary directory
+func writeOptionsFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	t.Setenv("PULSE_ENVIRONMENT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PORT", "")
+
+	tests := []struct {
+		name, file, content string
+	}{
+		{name: "json", file: "pulse.json", content: `{"telemetry": {"otlp": {"host": "collector", "port": 4318, "enabled": true}}}`},
+		{name: "yaml", file: "pulse.yaml", content: "telemetry:\n  otlp:\n    host: collector\n    port: 4318\n    enabled: true\n"},
+		{name: "yml", file: "pulse.yml", content: "telemetry:\n  otlp: {host: collector, port: 4318, enabled: true}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := LoadFromFile(writeOptionsFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("LoadFromFile() error = %v", err)
+			}
+			otlp := opts.Telemetry.OTLP
+			if otlp.Host != "collector" || otlp.Port != 4318 || !otlp.Enabled {
+				t.Errorf("OTLP = %+v, want collector:4318 enabled", otlp)
+			}
+			if !otlp.FailOpen || !opts.Telemetry.Logging.Enabled {
+				t.Error("options missing from the file lost their defaults")
+			}
+		})
+	}
+}
+
+func TestLoadFromFileEnvOverrides(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_PORT", "4319")
+
+	opts, err := LoadFromFile(writeOptionsFile(t, "pulse.json", `{"telemetry": {"otlp": {"port": 4318}}}`))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if opts.Telemetry.OTLP.Port != 4319 {
+		t.Errorf("port = %d, want 4319 from OTEL_EXPORTER_OTLP_PORT", opts.Telemetry.OTLP.Port)
+	}
+}
+
+func TestLoadFromFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, wantErr string
+	}{
+		{name: "unknown key", file: "pulse.json", content: `{"telemetry": {"otlp": {"hots": "collector"}}}`, wantErr: "unknown field"},
+		{name: "invalid yaml", file: "pulse.yaml", content: "telemetry: [", wantErr: "failed to parse"},
+		{name: "extension", file: "pulse.toml", content: "", wantErr: "unsupported options file extension"},
+		{name: "invalid value", file: "pulse.json", content: `{"foxglove": {"compression": "gzip"}}`, wantErr: "invalid options"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromFile(writeOptionsFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFromFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadFromFile() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,55 @@
+package options
+
+// LoggingOptions defines the settings for the console logger.
+type LoggingOptions struct {
+	Log               LogOptions             `json:"log"`               // Console log formatting options
+	Attributes        AttributeFilterOptions `json:"attributes"`        // Allow/deny policy for log attribute keys (OTLP, console, MCAP and RecentLogs)
+	DefaultAttributes map[string]interface{} `json:"defaultAttributes"` // Attributes added to every OTLP log record (log data takes precedence)
+
+	// Replace characters other than letters, digits and '_' in the keys of user attributes (log data, With,
+	// defaults and baggage) with '_' before the OTLP export (user.id becomes user_id), for backends such as Loki
+	// that only accept label names matching [a-zA-Z_][a-zA-Z0-9_]*. Renamed keys are listed in a pulse.original_keys
+	// attribute. Console and MCAP output keep the original keys.
+	SanitizeAttributeKeys bool `json:"sanitizeAttributeKeys"`
+}
+
+// TimeFormat is a string type that selects the timestamp layout used by the console logger.
+type TimeFormat string
+
+const (
+	TimeFormatRFC3339     TimeFormat = "rfc3339"     // 2006-01-02T15:04:05Z07:00
+	TimeFormatRFC3339Nano TimeFormat = "rfc3339nano" // 2006-01-02T15:04:05.999999999Z07:00
+	TimeFormatKitchen     TimeFormat = "kitchen"     // 3:04PM
+	TimeFormatStamp       TimeFormat = "stamp"       // Jan _2 15:04:05
+	TimeFormatCustom      TimeFormat = "custom"      // Uses LogOptions.CustomFormat
+)
+
+// LogOptions defines the formatting settings for console log output.
+type LogOptions struct {
+	ReportCaller    bool       `json:"reportCaller"`    // Report file:line of the caller
+	ReportTimestamp bool       `json:"reportTimestamp"` // Report the log timestamp
+	CallerOffset    int        `json:"callerOffset"`    // Number of stack frames to skip when reporting the caller
+	TimeFormatKey   TimeFormat `json:"timeFormat"`      // Timestamp layout (default: RFC3339)
+	CustomFormat    string     `json:"customFormat"`    // Custom time layout, used when TimeFormatKey is TimeFormatCustom
+
+	// Console line decorations
+	Prefix           string `json:"prefix"`           // Custom logger prefix (default: "name (version | environment)")
+	DisablePrefix    bool   `json:"disablePrefix"`    // Disable the logger prefix entirely
+	DisableCaller    bool   `json:"disableCaller"`    // Disable file:line caller reporting
+	DisableTimestamp bool   `json:"disableTimestamp"` // Disable timestamp reporting
+	IncludeHost      bool   `json:"includeHost"`      // Add the host name (pod name in Kubernetes) to every console line and OTLP record as host.name
+
+	// In-memory ring buffer of recent logs (optional)
+	RingBufferSize int `json:"ringBufferSize"` // Number of recent log entries to keep in memory (0 disables)
+
+	// Fatal behavior
+	FatalExitCode int  `json:"fatalExitCode"` // Exit code used by Fatal/Fatalf (default: 1)
+	FatalPanic    bool `json:"fatalPanic"`    // Panic instead of exiting, so tests can recover from Fatal
+
+	// Use the json tag name (or the field name) as the OTLP attribute key of struct fields without a pulse tag
+	AutoAttributes bool `json:"autoAttributes"`
+
+	// Print the top-level fields of struct or map data as separate console key/value pairs (user_id=alice)
+	// instead of a pretty-printed JSON blob under "data". Does not change OTLP or MCAP output.
+	FlattenData bool `json:"flattenData"`
+}
@@ -1,5 +1,7 @@
 package options
 
+import "time"
+
 // Package options provides configuration options for the pulse service.
 // It includes options for logging, metrics, tracing, and network settings.
 // The options are structured in a way that allows for easy customization
@@ -46,10 +48,77 @@ type NetworkOptions struct {
 
 // FoxgloveOptions defines the settings for Foxglove integration.
 type FoxgloveOptions struct {
-	Enabled  bool   `json:"enabled"`  // Enable MCAP logging
-	McapPath string `json:"filePath"` // Path to save MCAP files (e.g., "/var/logs/service.mcap")
+	Enabled           bool              `json:"enabled"`           // Enable MCAP logging
+	McapPath          string            `json:"filePath"`          // Path to save MCAP files (e.g., "/var/logs/service.mcap")
+	MetricChannelMode MetricChannelMode `json:"metricChannelMode"` // How metrics are split into MCAP channels (default: per metric)
+	Compression       McapCompression   `json:"compression"`       // MCAP chunk compression (default: zstd)
+	SharedWriter      McapWriter        `json:"-"`                 // Existing writer shared with other Pulse instances (see pulse.NewSharedMcapWriter); McapPath is ignored when set
+	LiveStream        LiveStreamOptions `json:"liveStream"`        // Stream MCAP channels live to Foxglove Studio over WebSocket (first output only when Outputs is set)
+	Outputs           []McapOutput      `json:"outputs"`           // Separate MCAP files per signal (e.g., logs and metrics in different files); McapPath is ignored when set
+	Clock             Clock             `json:"-"`                 // Time source of MCAP log and metric timestamps (default: real time), e.g. a fixed clock in tests
+
+	// Close the current chunk and sync the file to disk at least this often, so a process killed without
+	// Close (SIGKILL, power loss) loses at most about this many seconds of data (0 = only when a chunk is full)
+	FlushIntervalSeconds int `json:"flushIntervalSeconds"`
+
+	// Percentile summaries (p50/p95/p99) of histogram values, written as {name}.p50, {name}.p95 and {name}.p99 metrics
+	HistogramSummaries              bool `json:"histogramSummaries"`              // Enable histogram summaries
+	HistogramSummaryIntervalSeconds int  `json:"histogramSummaryIntervalSeconds"` // Summary window in seconds (default: 10)
+}
+
+// LiveStreamOptions defines the settings for streaming telemetry live to Foxglove Studio
+// using the foxglove-websocket protocol (Open connection > Foxglove WebSocket in Studio).
+type LiveStreamOptions struct {
+	Enabled bool   `json:"enabled"` // Enable the live stream server
+	Address string `json:"address"` // Listen address (default: ":8765")
+}
+
+// McapOutput defines one MCAP file and the signals routed to it.
+// Each signal may be routed to at most one output; signals not listed in any output are not recorded.
+type McapOutput struct {
+	Path    string       `json:"path"`    // Path to the MCAP file
+	Signals []McapSignal `json:"signals"` // Signals written to this file
 }
 
+// McapSignal is a string type that names a signal that can be recorded to MCAP.
+type McapSignal string
+
+const (
+	McapSignalLogs    McapSignal = "logs"    // Log messages
+	McapSignalMetrics McapSignal = "metrics" // Metric values
+	McapSignalTraces  McapSignal = "traces"  // Span timeline intervals
+	McapSignalSpatial McapSignal = "spatial" // Poses, transforms and markers (Pulse.RecordPose, RecordTransform, RecordSceneUpdate)
+)
+
+// Clock provides the current time. Set FoxgloveOptions.Clock to a fixed or stepped clock
+// to record deterministic MCAP timestamps in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// McapWriter is an MCAP writer that can be shared by several Pulse instances in one process.
+// Create one with pulse.NewSharedMcapWriter.
+type McapWriter interface {
+	GetFilePath() string
+}
+
+// McapCompression is a string type that selects the compression of MCAP chunks.
+type McapCompression string
+
+const (
+	McapCompressionZSTD McapCompression = "zstd" // Best compression ratio (default)
+	McapCompressionLZ4  McapCompression = "lz4"  // Faster, lower CPU usage (recommended for edge devices)
+	McapCompressionNone McapCompression = "none" // No compression
+)
+
+// MetricChannelMode is a string type that selects how metrics are written to MCAP channels.
+type MetricChannelMode string
+
+const (
+	MetricChannelPerMetric MetricChannelMode = "per_metric" // One channel per metric name and attribute set (default)
+	MetricChannelSingle    MetricChannelMode = "single"     // All metrics on one channel, distinguished by name/attributes fields
+)
+
 // OTELOptions defines the settings for OpenTelemetry.
 // It includes the host and port for the OpenTelemetry collector.
 type OTELOptions struct {
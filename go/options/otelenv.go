@@ -0,0 +1,69 @@
+package options
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Standard OpenTelemetry SDK environment variables, so Pulse is configured like the other OTel SDKs.
+// They only change the defaults; options set in code still win.
+const (
+	envSDKDisabled      = "OTEL_SDK_DISABLED"           // "true" disables logging, metrics and tracing telemetry
+	envTracesExporter   = "OTEL_TRACES_EXPORTER"        // "none" disables tracing telemetry
+	envMetricsExporter  = "OTEL_METRICS_EXPORTER"       // "none" disables metrics telemetry
+	envLogsExporter     = "OTEL_LOGS_EXPORTER"          // "none" disables logging telemetry
+	envOTLPEndpoint     = "OTEL_EXPORTER_OTLP_ENDPOINT" // Collector URL (e.g., "http://collector:4317"), enables OTLP export
+	envOTLPCompression  = "OTEL_EXPORTER_OTLP_COMPRESSION"
+	envMetricExportIntv = "OTEL_METRIC_EXPORT_INTERVAL" // Milliseconds
+)
+
+// otelSignalEnabled reports whether a signal is enabled by default: false if OTEL_SDK_DISABLED is true
+// or the signal's exporter variable (e.g., OTEL_TRACES_EXPORTER) is "none"
+func otelSignalEnabled(exporterKey string) bool {
+	if getBoolFromEnvOrDefault(envSDKDisabled, false) {
+		return false
+	}
+	return !strings.EqualFold(os.Getenv(exporterKey), "none")
+}
+
+// otelEndpointFromEnv returns the host and port of OTEL_EXPORTER_OTLP_ENDPOINT.
+// The scheme is optional; a missing port defaults to 4317. ok is false if the variable is unset or invalid.
+func otelEndpointFromEnv() (host string, port int, ok bool) {
+	value := os.Getenv(envOTLPEndpoint)
+	if value == "" {
+		return "", 0, false
+	}
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Hostname() == "" {
+		return "", 0, false
+	}
+
+	port = 4317
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", 0, false
+		}
+	}
+	return u.Hostname(), port, true
+}
+
+// otelMetricExportIntervalSeconds returns OTEL_METRIC_EXPORT_INTERVAL converted to seconds (at least 1),
+// or defaultValue if the variable is unset or invalid
+func otelMetricExportIntervalSeconds(defaultValue int) int {
+	millis := getIntFromEnvOrDefault(envMetricExportIntv, 0)
+	if millis <= 0 {
+		return defaultValue
+	}
+	return max(millis/1000, 1)
+}
+
+// otelCompressionFromEnv returns OTEL_EXPORTER_OTLP_COMPRESSION ("gzip" or "none"), or "" if unset
+func otelCompressionFromEnv() OTLPCompression {
+	return OTLPCompression(strings.ToLower(os.Getenv(envOTLPCompression)))
+}
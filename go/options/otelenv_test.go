@@ -0,0 +1,63 @@
+package options
+
+import "testing"
+
+func TestOtelEndpointFromEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantHost string
+		wantPort int
+		wantOK   bool
+	}{
+		{value: ""},
+		{value: "http://collector:4317", wantHost: "collector", wantPort: 4317, wantOK: true},
+		{value: "collector:4318", wantHost: "collector", wantPort: 4318, wantOK: true},
+		{value: "https://collector", wantHost: "collector", wantPort: 4317, wantOK: true},
+		{value: "http://[::1]:4317", wantHost: "::1", wantPort: 4317, wantOK: true},
+		{value: "http://:4317"},
+		{value: "http://collector:port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(envOTLPEndpoint, tt.value)
+
+			host, port, ok := otelEndpointFromEnv()
+			if host != tt.wantHost || port != tt.wantPort || ok != tt.wantOK {
+				t.Errorf("otelEndpointFromEnv() = %q, %d, %v, want %q, %d, %v", host, port, ok, tt.wantHost, tt.wantPort, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestOtelSignalEnabled(t *testing.T) {
+	tests := []struct {
+		name, disabled, exporter string
+		want                     bool
+	}{
+		{name: "unset", want: true},
+		{name: "sdk disabled", disabled: "true", want: false},
+		{name: "exporter none", exporter: "NONE", want: false},
+		{name: "exporter otlp", exporter: "otlp", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envSDKDisabled, tt.disabled)
+			t.Setenv(envTracesExporter, tt.exporter)
+
+			if got := otelSignalEnabled(envTracesExporter); got != tt.want {
+				t.Errorf("otelSignalEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOtelMetricExportIntervalSeconds(t *testing.T) {
+	for value, want := range map[string]int{"": 10, "60000": 60, "500": 1, "abc": 10, "-1": 10} {
+		t.Setenv(envMetricExportIntv, value)
+		if got := otelMetricExportIntervalSeconds(10); got != want {
+			t.Errorf("OTEL_METRIC_EXPORT_INTERVAL=%q: got %d, want %d", value, got, want)
+		}
+	}
+}
@@ -28,4 +28,14 @@ type ProfilingOptions struct {
 	
 	// Custom tags (optional)
 	Tags map[string]string `json:"tags"` // Additional tags to attach to profiles
+
+	// Also record the latency (profiling.operation.duration_ms histogram) and outcome (profiling.operation.total
+	// counter, with a status attribute) of the profiling helpers (all but ProfileSection) as metrics, with their
+	// profiling tags as attributes (except the cache key and memory size). Works without continuous profiling enabled.
+	RecordMetrics bool `json:"recordMetrics"`
+
+	// Preset for constrained devices (set by default on Jetson): disables the allocation profiles, enables
+	// goroutine profiles, uploads every 60s instead of 15s and skips the GC that is otherwise forced before
+	// a heap profile when none ran since the last upload. CPU and in-use profiles are kept as configured.
+	LowOverhead bool `json:"lowOverhead"`
 }
@@ -0,0 +1,12 @@
+package options
+
+// SamplingParams exposes the span information available when a sampling decision is made
+type SamplingParams struct {
+	SpanName      string                 // Name of the span being started
+	TraceID       string                 // Trace ID of the span (hex encoded)
+	ParentSampled bool                   // Whether the parent span (if any) was sampled
+	Attributes    map[string]interface{} // Attributes provided at span start
+}
+
+// SamplerFunc decides at span start whether a span should be sampled (recorded and exported)
+type SamplerFunc func(SamplingParams) bool
@@ -3,31 +3,155 @@ package options
 // TelemetryOptions defines the configuration for the unified telemetry service
 // that integrates OpenTelemetry for logging, metrics, and tracing.
 type TelemetryOptions struct {
-	Logging LoggingTelemetryOptions `json:"logging"` // Logging telemetry options
-	Metrics MetricsTelemetryOptions `json:"metrics"` // Metrics telemetry options
-	Tracing TracingTelemetryOptions `json:"tracing"` // Tracing telemetry options
-	OTLP    OTLPOptions             `json:"otlp"`    // OTLP exporter options
+	Logging  LoggingTelemetryOptions `json:"logging"`  // Logging telemetry options
+	Metrics  MetricsTelemetryOptions `json:"metrics"`  // Metrics telemetry options
+	Tracing  TracingTelemetryOptions `json:"tracing"`  // Tracing telemetry options
+	OTLP     OTLPOptions             `json:"otlp"`     // OTLP exporter options
+	Resource ResourceOptions         `json:"resource"` // Resource detectors merged into the service resource
+
+	defaultsFor Environment // Environment of the defaults these options were created from, see DefaultsEnvironment
+}
+
+// DefaultsEnvironment returns the environment whose defaults (sampling ratio, export interval, ...) the
+// options were created from by Default, DefaultTelemetry, their ForEnvironment variants or LoadFromFile.
+// Returns ok=false for options built as a struct literal.
+func (o TelemetryOptions) DefaultsEnvironment() (env Environment, ok bool) {
+	return o.defaultsFor, o.defaultsFor != ""
+}
+
+// ResourceOptions selects the OpenTelemetry resource detectors used to describe the running process.
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME are always read and override the service options.
+type ResourceOptions struct {
+	Host      bool `json:"host"`      // Detect host name and ID (default: true)
+	Process   bool `json:"process"`   // Detect PID, executable name/path, owner and Go runtime, without command args (default: true)
+	Container bool `json:"container"` // Detect container ID from cgroups
 }
 
 // LoggingTelemetryOptions defines the configuration for OpenTelemetry logging
 type LoggingTelemetryOptions struct {
-	Enabled bool `json:"enabled"` // Enable logging
+	Enabled  bool   `json:"enabled"`  // Enable logging
+	FilePath string `json:"filePath"` // Also write every log record as a JSON line to this file, e.g. for air-gapped upload later (empty disables)
+
+	OTLP OTLPEndpointOptions `json:"otlp"` // Collector of the logs, e.g. Loki's OTLP endpoint (default: TelemetryOptions.OTLP)
 }
 
 // MetricsTelemetryOptions defines the configuration for OpenTelemetry metrics
 type MetricsTelemetryOptions struct {
 	Enabled               bool `json:"enabled"`               // Enable metrics
 	ExportIntervalSeconds int  `json:"exportIntervalSeconds"` // Export interval in seconds
+	CardinalityLimit      int  `json:"cardinalityLimit"`      // Max attribute sets per metric before collapsing into an overflow series (0 = unlimited)
+
+	OTLP OTLPEndpointOptions `json:"otlp"` // Collector of the metrics (default: TelemetryOptions.OTLP)
+
+	// Attributes added to every metric (struct attributes take precedence)
+	DefaultAttributes map[string]interface{} `json:"defaultAttributes"`
+
+	// Keys copied from the recording context (span attribute, else baggage member) into every metric
+	// recorded with Metrics.WithContext or RecordCtx, e.g. ["route", "tenant"]; struct attributes take precedence
+	ContextAttributes []string `json:"contextAttributes"`
+
+	// Exponential (base-2) histograms for better tail resolution
+	ExponentialHistograms     bool     `json:"exponentialHistograms"`     // Use exponential aggregation for all histograms
+	ExponentialHistogramNames []string `json:"exponentialHistogramNames"` // Use exponential aggregation for matching histogram names (wildcards "*" and "?")
+
+	// Views rename, drop or reduce the attributes of matching instruments, including third-party ones
+	Views []MetricViewOptions `json:"views"`
+
+	// Print a warning (once per type) when Metrics.Record gets a struct without metric tags, which records
+	// nothing. Default() enables it in development.
+	WarnUntagged bool `json:"warnUntagged"`
+}
+
+// MetricViewOptions defines an OpenTelemetry view applied to instruments whose name matches MatchName
+type MetricViewOptions struct {
+	MatchName      string   `json:"matchName"`      // Instrument name to match (wildcards "*" and "?")
+	Rename         string   `json:"rename"`         // New instrument name (only for MatchName without wildcards)
+	Drop           bool     `json:"drop"`           // Drop the instrument from the export
+	KeepAttributes []string `json:"keepAttributes"` // Only keep these attribute keys (empty keeps all)
 }
 
 // TracingTelemetryOptions defines the configuration for OpenTelemetry tracing
 type TracingTelemetryOptions struct {
-	Enabled bool `json:"enabled"` // Enable tracing
+	Enabled bool        `json:"enabled"` // Enable tracing
+	Sampler SamplerFunc `json:"-"`       // Custom sampling decision (default: sample every span)
+
+	OTLP OTLPEndpointOptions `json:"otlp"` // Collector of the traces, e.g. Tempo (default: TelemetryOptions.OTLP)
+
+	// Fraction of traces sampled when neither Sampler nor OTEL_TRACES_SAMPLER is set: root spans are sampled
+	// by trace ID ratio and children follow their parent, so 0 drops every new trace. Nil samples every span.
+	// Default() sets 0.1 in production (PULSE_TRACES_SAMPLE_RATIO overrides). Set it with options.Ratio.
+	SampleRatio *float64 `json:"sampleRatio"`
+
+	SpanLimits          SpanLimitsOptions   `json:"spanLimits"`          // Per-span limits on attributes, events and links
+	RecordSpanDurations bool                `json:"recordSpanDurations"` // Record every span's duration in the span.duration histogram (by span name and status)
+	TailSampling        TailSamplingOptions `json:"tailSampling"`        // Keep only traces that contain an error
+
+	// Export each span synchronously when it ends instead of batching, for CLI tools and short jobs
+	// that exit right after their last span. Span.End blocks on the export, so keep it off for services.
+	SyncExport bool `json:"syncExport"`
+}
+
+// TailSamplingOptions configures in-process tail sampling: ended spans are buffered per trace and the
+// trace is exported only if one of its spans has error status or was force-sampled (see TracingOptions.DebugHeader).
+// Traces whose root span lives in another process are decided when the window elapses.
+type TailSamplingOptions struct {
+	Enabled       bool `json:"enabled"`       // Enable tail sampling (default: false, export every sampled span)
+	WindowSeconds int  `json:"windowSeconds"` // Max time a trace is buffered before it is decided (default: 10)
+	MaxTraces     int  `json:"maxTraces"`     // Max buffered traces; the oldest is decided when full (default: 10000)
+}
+
+// SpanLimitsOptions bounds the size of individual spans. Zero values keep the SDK defaults
+// (128 attributes, events and links, unlimited attribute value length, or the OTEL_SPAN_* environment variables).
+type SpanLimitsOptions struct {
+	MaxAttributes           int `json:"maxAttributes"`           // Max attributes per span
+	MaxEvents               int `json:"maxEvents"`               // Max events per span
+	MaxLinks                int `json:"maxLinks"`                // Max links per span
+	MaxAttributeValueLength int `json:"maxAttributeValueLength"` // Max length of string attribute values (longer values are truncated)
 }
 
 // OTLPOptions defines the settings for OTLP exporter
 type OTLPOptions struct {
-	Host    string `json:"host"`    // OTLP collector host (e.g., "localhost")
-	Port    int    `json:"port"`    // OTLP collector port (e.g., 4317 for gRPC)
-	Enabled bool   `json:"enabled"` // Enable OTLP export (if false, uses stdout)
+	Host     string `json:"host"`     // OTLP collector host (default: localhost)
+	Port     int    `json:"port"`     // OTLP collector port (e.g., 4317 for gRPC)
+	Enabled  bool   `json:"enabled"`  // Enable OTLP export (if false, uses stdout)
+	FailOpen bool   `json:"failOpen"` // If exporter setup fails, disable that signal instead of failing (default: true)
+
+	// Unix domain socket of a local collector (e.g., "/run/otel/otlp.sock" or "unix:///run/otel/otlp.sock"),
+	// used instead of Host:Port. Cannot be combined with Host.
+	UnixSocket string `json:"unixSocket"`
+
+	// Request compression, per signal so large log streams can be compressed without paying CPU for small ones
+	Compression       OTLPCompression `json:"compression"`       // Default compression for all signals (default: none)
+	LogCompression    OTLPCompression `json:"logCompression"`    // Log exporter compression (default: Compression)
+	TraceCompression  OTLPCompression `json:"traceCompression"`  // Trace exporter compression (default: Compression)
+	MetricCompression OTLPCompression `json:"metricCompression"` // Metric exporter compression (default: Compression)
+
+	Retry OTLPRetryOptions `json:"retry"` // Retry of failed exports, e.g. during a collector restart
+}
+
+// OTLPEndpointOptions overrides the collector endpoint of OTLPOptions for one signal, for deployments with a
+// separate backend per signal. Unset fields use the shared OTLPOptions; setting Host or UnixSocket replaces
+// both. Export is still enabled by OTLPOptions.Enabled, and compression is set per signal in OTLPOptions.
+type OTLPEndpointOptions struct {
+	Host       string `json:"host"`       // Collector host (default: OTLPOptions.Host)
+	Port       int    `json:"port"`       // Collector port (default: OTLPOptions.Port)
+	UnixSocket string `json:"unixSocket"` // Unix domain socket of a local collector, used instead of Host:Port
 }
+
+// OTLPRetryOptions defines the exponential backoff retry of failed OTLP exports.
+// Zero intervals use the exporter defaults (5s initial, 30s max interval, 1m max elapsed time).
+type OTLPRetryOptions struct {
+	Enabled                bool `json:"enabled"`                // Retry failed exports (default: true)
+	InitialIntervalSeconds int  `json:"initialIntervalSeconds"` // Wait after the first failure
+	MaxIntervalSeconds     int  `json:"maxIntervalSeconds"`     // Upper bound of the backoff interval
+	MaxElapsedTimeSeconds  int  `json:"maxElapsedTimeSeconds"`  // Give up (and drop the batch) after this long
+}
+
+// OTLPCompression is a string type that selects the compression of OTLP export requests.
+type OTLPCompression string
+
+const (
+	OTLPCompressionNone OTLPCompression = "none" // No compression
+	OTLPCompressionGzip OTLPCompression = "gzip" // gzip, supported by every OTLP collector
+	OTLPCompressionZstd OTLPCompression = "zstd" // zstd, better ratio and speed (the collector must accept zstd)
+)
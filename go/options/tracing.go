@@ -2,5 +2,29 @@ package options
 
 // TracingOptions defines the options for distributed tracing.
 type TracingOptions struct {
-	Enabled bool `json:"enabled"` // Enable distributed tracing
+	Enabled           bool                   `json:"enabled"`           // Enable distributed tracing
+	Attributes        AttributeFilterOptions `json:"attributes"`        // Allow/deny policy for span attribute keys
+	DefaultAttributes map[string]interface{} `json:"defaultAttributes"` // Attributes added to every span (struct attributes take precedence)
+	IgnoreSpanNames   []string               `json:"ignoreSpanNames"`   // Span names (exact or path.Match glob, e.g. "GET /healthz*") started as non-recording spans
+	HeaderAttributes  map[string]string      `json:"headerAttributes"`  // HTTP header -> attribute key set on request spans and baggage by Tracing.HTTPMiddleware (e.g., "X-Tenant-ID": "tenant.id")
+
+	// HTTP header (e.g., "X-Debug-Trace") that makes Tracing.HTTPMiddleware force-sample the request
+	// regardless of the sampler when set to a true value ("1", "true"). Strip it at the edge for untrusted traffic.
+	DebugHeader string `json:"debugHeader"`
+
+	// Rewrites span names in Tracing.Start before IgnoreSpanNames is matched, e.g. to strip IDs that would
+	// otherwise make every name unique (pulse.NormalizeSpanName replaces UUIDs and numbers)
+	SpanNameNormalizer func(name string) string `json:"-"`
+
+	// Add the calling function as code.function, code.filepath and code.lineno attributes to spans started
+	// with Tracing.Start, Trace and StartWithAttrs (not HTTPMiddleware). Costs a stack walk per span.
+	RecordCaller bool `json:"recordCaller"`
+
+	// Keep ended spans in memory so tests can assert on them with Tracing.RecordedSpans. Works without an
+	// exporter or collector; spans are kept until Tracing.ResetRecordedSpans, so don't enable it in production.
+	RecordSpans bool `json:"recordSpans"`
+
+	// Print a warning (once per type) when Tracing.Start gets a data struct without trace tags, which adds
+	// no attributes. Default() enables it in development.
+	WarnUntagged bool `json:"warnUntagged"`
 }
@@ -2,19 +2,128 @@ package pulse
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/machanirobotics/pulse/go/internal/foxglove"
 	"github.com/machanirobotics/pulse/go/internal/logging"
 	"github.com/machanirobotics/pulse/go/internal/metrics"
 	"github.com/machanirobotics/pulse/go/internal/profiling"
+	"github.com/machanirobotics/pulse/go/internal/tags"
 	"github.com/machanirobotics/pulse/go/internal/telemetry"
 	"github.com/machanirobotics/pulse/go/internal/tracing"
 	"github.com/machanirobotics/pulse/go/options"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // Span is a type alias for tracing.Span to avoid exposing internal packages
 type Span = tracing.Span
 
+// SpanMetrics is a type alias for tracing.SpanMetrics returned by Span.Metrics
+type SpanMetrics = tracing.SpanMetrics
+
+// TraceOptions is a type alias for tracing.TraceOptions, the span start options of Tracing.TraceWith
+type TraceOptions = tracing.TraceOptions
+
+// SpanStub is a type alias for tracing.SpanStub returned by Tracing.RecordedSpans
+type SpanStub = tracing.SpanStub
+
+// LogEntry is a type alias for logging.LogEntry returned by Logger.RecentLogs
+type LogEntry = logging.LogEntry
+
+// KeyValue is a type alias for logging.KeyValue (an OpenTelemetry log attribute), used for per-call log attributes
+type KeyValue = logging.KeyValue
+
+// ContextLogger is a type alias for logging.ContextLogger, a context-first adapter returned by Logger.ContextLogger
+type ContextLogger = logging.ContextLogger
+
+// Attr creates a per-call log attribute passed after the primary log data.
+// Per-call attributes take precedence over fields of the primary data with the same key.
+//
+//	p.Logger.Info("Order placed", order, pulse.Attr("retry", 2))
+func Attr(key string, value any) KeyValue {
+	return logging.Attr(key, value)
+}
+
+// LogLevel is a type alias for logging.Level used by Logger.Log and Logger.RegisterLevel
+type LogLevel = logging.Level
+
+// Log levels, including the custom Trace (below Debug) and Notice (between Info and Warn) levels
+const (
+	TraceLevel  = logging.TraceLevel
+	DebugLevel  = logging.DebugLevel
+	InfoLevel   = logging.InfoLevel
+	NoticeLevel = logging.NoticeLevel
+	WarnLevel   = logging.WarnLevel
+	ErrorLevel  = logging.ErrorLevel
+	FatalLevel  = logging.FatalLevel
+)
+
+// SharedMcapWriter is a type alias for foxglove.UnifiedMcapWriter, used to share one MCAP file between Pulse instances
+type SharedMcapWriter = foxglove.UnifiedMcapWriter
+
+// NewSharedMcapWriter creates an MCAP writer that several Pulse instances in the same process can write to.
+// Pass it as FoxgloveOptions.SharedWriter to each instance. Logs and metrics are already namespaced by service
+// name; span timelines are written to /traces/timeline/{service}. The file is closed when the last instance
+// using it is closed. Options such as MetricChannelMode are taken from foxgloveOpts.
+func NewSharedMcapWriter(serviceOpts options.ServiceOptions, foxgloveOpts options.FoxgloveOptions) (*SharedMcapWriter, error) {
+	return foxglove.NewSharedUnifiedMcapWriter(serviceOpts, foxgloveOpts)
+}
+
+// Pulse struct tag grammar: `pulse:"attribute:key"`, `pulse:"trace:key"` and `pulse:"metric:type:name"`
+const (
+	TagAttribute = tags.KindAttribute // Log attribute tag kind
+	TagTrace     = tags.KindTrace     // Span attribute tag kind
+	TagMetric    = tags.KindMetric    // Metric tag kind
+
+	MetricCounter   = tags.MetricCounter   // Counter metric type
+	MetricHistogram = tags.MetricHistogram // Histogram metric type
+	MetricGauge     = tags.MetricGauge     // Gauge metric type
+
+	ModifierParse   = tags.ModifierParse   // Metric tag modifier parsing string fields as numbers (`pulse:"metric:counter:bytes;parse"`)
+	ModifierBuckets = tags.ModifierBuckets // Histogram tag modifier setting bucket boundaries (`pulse:"metric:histogram:latency_ms;buckets=5,10,50"`)
+	ModifierMode    = tags.ModifierMode    // Counter tag modifier setting the recording mode (`pulse:"metric:counter:tokens;mode=total"`)
+
+	ModeDelta = tags.ModeDelta // Counter mode: the field holds the increment since the last Record (default)
+	ModeTotal = tags.ModeTotal // Counter mode: the field holds a running total, only its increase is added
+)
+
+// Errors returned (wrapped) for pulse struct tags that do not match the tag grammar. Every tag error
+// matches ErrMalformedTag; unknown metric types and invalid metric names also match the specific error.
+var (
+	ErrMalformedTag      = tags.ErrMalformedTag
+	ErrUnknownMetricType = tags.ErrUnknownMetricType
+	ErrInvalidMetricName = tags.ErrInvalidMetricName
+)
+
+// TagFieldError is a type alias for tags.FieldError, the tag error of a struct field returned by
+// ValidateStruct, Metrics.Validate and Metrics.Record (use errors.As to get the field name)
+type TagFieldError = tags.FieldError
+
+// ErrMetricTypeConflict is returned (wrapped) by Metrics.Record and Metrics.Validate when a metric name
+// is declared with two different types (e.g., a counter and a gauge)
+var ErrMetricTypeConflict = metrics.ErrMetricTypeConflict
+
+// ForceSampling returns ctx marked so that spans started with it, and their children, are sampled
+// regardless of the configured sampler (and kept by tail sampling). Tracing.HTTPMiddleware does this
+// for requests carrying TracingOptions.DebugHeader; use it directly for other transports.
+func ForceSampling(ctx context.Context) context.Context {
+	return telemetry.ContextWithForcedSampling(ctx)
+}
+
+// NormalizeSpanName replaces UUIDs with {uuid} and numbers with {id} in a span name.
+// Set it as TracingOptions.SpanNameNormalizer to guard against high-cardinality span names.
+func NormalizeSpanName(name string) string {
+	return tracing.NormalizeSpanName(name)
+}
+
+// ValidateStruct checks the pulse struct tags of v and returns one error per malformed tag.
+// Call it at startup to catch tag typos (e.g. `pulse:"traces:user.id"`) that would otherwise be ignored.
+func ValidateStruct(v any) []error {
+	return tags.ValidateStruct(v)
+}
+
 // Pulse is the main framework struct that provides access to all telemetry services.
 // It supports both the legacy logging interface and the new unified OpenTelemetry-based telemetry.
 type Pulse struct {
@@ -29,42 +138,204 @@ type Pulse struct {
 
 	// Unified OpenTelemetry-based telemetry
 	telemetry *telemetry.Telemetry
-	// Unified MCAP writer for both logs and metrics
-	unifiedMcap *foxglove.UnifiedMcapWriter
+	// MCAP writers of logs, metrics and traces (one unified file unless FoxgloveOptions.Outputs is set)
+	mcap *mcapWriters
+
+	// Whether this instance was derived with WithContext (Close is a no-op)
+	derived bool
+
+	// Callbacks registered with OnShutdown (shared with instances from WithContext)
+	shutdown *shutdownHooks
+
+	// Close runs once; later and concurrent calls return the first call's result
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // New creates a new Pulse instance with both legacy and unified telemetry services.
 // The unified telemetry service (Telemetry) is the recommended approach for new applications.
 func New(ctx context.Context, serviceOpts options.ServiceOptions, opts options.PulseOptions) (*Pulse, error) {
+	// Defaults from PULSE_ENVIRONMENT can silently disagree with the service environment (e.g. no sampling in production)
+	if env, ok := opts.Telemetry.DefaultsEnvironment(); ok && serviceOpts.Environment != "" && env != serviceOpts.Environment {
+		fmt.Printf("Warning: options use the %s defaults but the service environment is %s, use options.DefaultForEnvironment(serviceOpts.Environment)\n",
+			env, serviceOpts.Environment)
+	}
+
 	// Initialize unified telemetry service
 	tel, err := telemetry.New(ctx, serviceOpts, opts.Telemetry)
 	if err != nil {
 		return nil, err
 	}
 
-	// Initialize unified MCAP writer if Foxglove is enabled
-	var unifiedMcap *foxglove.UnifiedMcapWriter
-	if opts.Foxglove.Enabled && opts.Foxglove.McapPath != "" {
-		unifiedMcap, err = foxglove.NewUnifiedMcapWriter(serviceOpts, opts.Foxglove)
+	// Initialize MCAP writers if Foxglove is enabled, reusing a shared writer if one is provided
+	mcap, err := newMcapWriters(serviceOpts, opts.Foxglove)
+	if err != nil {
+		_ = tel.Shutdown(ctx)
+		return nil, err
+	}
+	if err := mcap.writeResource(tel.ResourceAttributes()); err != nil {
+		mcap.release()
+		_ = tel.Shutdown(ctx)
+		return nil, err
+	}
+
+	// Record spans in memory before the tracer is handed to Tracing, which may create the tracer provider
+	var recorder *tracetest.SpanRecorder
+	if opts.Tracing.RecordSpans {
+		recorder = tel.RecordSpans()
+	}
+
+	// Write finished spans to the MCAP timeline, creating a tracer provider if OTLP is disabled
+	if mcap.traces != nil && opts.Tracing.Enabled && opts.Telemetry.Tracing.Enabled {
+		timeline, err := tracing.NewSpanMcapWriter(serviceOpts, mcap.traces)
 		if err != nil {
-			return nil, err
+			// Continue without the timeline - spans are still exported via OTLP
+			fmt.Printf("Warning: Failed to initialize MCAP span writer: %v\n", err)
+		} else {
+			tel.RegisterSpanProcessor(timeline)
 		}
 	}
 
+	// Metrics are shared with Tracing, which records SpanMetrics counts through them
+	m := metrics.NewMetrics(serviceOpts, opts.Telemetry.Metrics, mcap.metrics, tel.GetMetrics())
+
 	p := &Pulse{
-		telemetry:   tel,
-		unifiedMcap: unifiedMcap,
-		Logger:      logging.NewLogger(serviceOpts, opts.Logging, unifiedMcap, tel.GetLogger()),
-		Metrics:     metrics.NewMetrics(serviceOpts, unifiedMcap, tel.GetMetrics()),
-		Tracing:     tracing.NewTracing(serviceOpts, opts.Tracing, unifiedMcap, tel.GetTracer()),
-		Profiler:    profiling.NewProfiler(serviceOpts, opts.Profiling, unifiedMcap),
+		telemetry: tel,
+		mcap:      mcap,
+		shutdown:  &shutdownHooks{},
+		Logger:    logging.NewLogger(serviceOpts, opts.Logging, mcap.logs, tel.GetLogger(), tel.GetMetrics()),
+		Metrics:   m,
+		Tracing:   tracing.NewTracing(serviceOpts, opts.Tracing, mcap.traces, tel.GetTracer(), m, recorder),
+		Profiler:  profiling.NewProfiler(serviceOpts, opts.Profiling, mcap.metrics, m),
+	}
+
+	// Flush OTLP and MCAP before Fatal exits the program
+	p.Logger.SetFlushHook(p.flush)
+
+	// Report signals disabled at startup (OTLPOptions.FailOpen)
+	for _, initErr := range tel.InitErrors() {
+		p.Logger.Warn("Telemetry signal disabled", map[string]interface{}{"error": initErr.Error()})
+	}
+
+	// Report flags that disagree, which would otherwise silently drop spans
+	for _, mismatch := range optionMismatches(opts) {
+		p.Logger.Warn("Inconsistent Pulse options", map[string]interface{}{"problem": mismatch})
 	}
 
 	return p, nil
 }
 
-// Shutdown gracefully shuts down all telemetry services
+// optionMismatches reports signal flags that are set in one place but not the other.
+// Tracing is gated twice: TelemetryOptions.Tracing.Enabled sets up the SDK pipeline and
+// TracingOptions.Enabled makes Tracing.Start create spans; both are needed to record spans.
+func optionMismatches(opts options.PulseOptions) []string {
+	var mismatches []string
+	if opts.Tracing.Enabled && !opts.Telemetry.Tracing.Enabled {
+		mismatches = append(mismatches, "Tracing.Enabled is set but Telemetry.Tracing.Enabled is not, spans are not recorded")
+	}
+	if opts.Telemetry.Tracing.Enabled && !opts.Tracing.Enabled {
+		mismatches = append(mismatches, "Telemetry.Tracing.Enabled is set but Tracing.Enabled is not, Tracing.Start returns no-op spans")
+	}
+	return mismatches
+}
+
+// WithContext returns a Pulse whose Logger, Metrics and Tracing clients are bound to ctx.
+// Useful for passing a per-request telemetry bundle down the stack.
+// The returned Pulse shares resources with p; closing it is a no-op, close p instead.
+func (p *Pulse) WithContext(ctx context.Context) *Pulse {
+	return &Pulse{
+		Logger:    p.Logger.WithContext(ctx),
+		Metrics:   p.Metrics.WithContext(ctx),
+		Tracing:   p.Tracing.WithContext(ctx),
+		Profiler:  p.Profiler,
+		telemetry: p.telemetry,
+		mcap:      p.mcap,
+		shutdown:  p.shutdown,
+		derived:   true,
+	}
+}
+
+// errorLog is logged by HandleError, with the error fields as OTLP log attributes
+type errorLog struct {
+	Error     string `json:"error" pulse:"attribute:error"`
+	ErrorType string `json:"error_type" pulse:"attribute:error.type"`
+}
+
+// errorMetric is recorded by HandleError for every handled error
+type errorMetric struct {
+	Count     int    `pulse:"metric:counter:errors.total"`
+	ErrorType string `pulse:"attribute:error.type"`
+}
+
+// HandleError reports an error on every signal in one call:
+// it records the error on the span from ctx, logs msg at error level with trace correlation,
+// and increments the errors.total counter with an error.type attribute.
+// A nil error is ignored.
+func (p *Pulse) HandleError(ctx context.Context, err error, msg string) {
+	if err == nil {
+		return
+	}
+
+	errType := errorType(err)
+
+	p.Tracing.SpanFromContext(ctx).SetError(err)
+
+	p.Logger.WithContext(ctx).Error(msg, errorLog{Error: err.Error(), ErrorType: errType})
+
+	_ = p.Metrics.RecordCtx(ctx, errorMetric{Count: 1, ErrorType: errType}) // Ignore error, reporting must not fail the caller
+}
+
+// errorType returns the Go type of the innermost wrapped error (e.g., "*os.PathError")
+func errorType(err error) string {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = inner
+	}
+}
+
+// flush exports pending telemetry and finalizes the MCAP file.
+// Used before the program exits on a fatal log.
+func (p *Pulse) flush(ctx context.Context) error {
+	// Close the MCAP writers so the files get their summary and footer
+	if p.Metrics != nil {
+		_ = p.Metrics.Close() // Write pending histogram summaries first
+	}
+	if p.mcap != nil {
+		p.mcap.close()
+	}
+
+	if p.telemetry != nil {
+		return p.telemetry.ForceFlush(ctx)
+	}
+	return nil
+}
+
+// Close gracefully shuts down all telemetry services.
+// It is safe to call more than once and from several goroutines (e.g. a shutdown hook and a
+// signal handler): only the first call shuts down, the others wait for it and return its result.
 func (p *Pulse) Close(ctx context.Context) error {
+	// Instances from WithContext share resources with their parent
+	if p.derived {
+		return nil
+	}
+
+	p.closeOnce.Do(func() {
+		p.closeErr = p.close(ctx)
+	})
+	return p.closeErr
+}
+
+// close shuts down all telemetry services (see Close)
+func (p *Pulse) close(ctx context.Context) error {
+	// Run OnShutdown callbacks while logging, metrics and MCAP still work
+	var hookErr error
+	if p.shutdown != nil {
+		hookErr = p.shutdown.run(ctx)
+	}
+
 	// Stop profiler first to flush remaining data
 	if p.Profiler != nil {
 		if err := p.Profiler.Stop(); err != nil {
@@ -75,16 +346,17 @@ func (p *Pulse) Close(ctx context.Context) error {
 		}
 	}
 
-	// Close unified MCAP writer first (before logger tries to log about it)
-	if p.unifiedMcap != nil {
-		_ = p.unifiedMcap.Close() // Ignore error during shutdown
-	}
-
-	// Close metrics (no-op since unified writer is already closed)
+	// Close metrics before the MCAP writer so pending histogram summaries are written
 	if p.Metrics != nil {
 		_ = p.Metrics.Close() // Ignore error during shutdown
 	}
 
+	// Release MCAP writers first (before logger tries to log about it).
+	// A shared writer is only closed once the last Pulse using it is closed.
+	if p.mcap != nil {
+		p.mcap.release()
+	}
+
 	// Close logger (no-op since unified writer is already closed)
 	if p.Logger != nil {
 		_ = p.Logger.Close() // Ignore error during shutdown
@@ -96,7 +368,7 @@ func (p *Pulse) Close(ctx context.Context) error {
 	}
 
 	if p.telemetry != nil {
-		return p.telemetry.Shutdown(ctx)
+		return errors.Join(hookErr, p.telemetry.Shutdown(ctx))
 	}
-	return nil
+	return hookErr
 }
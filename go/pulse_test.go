@@ -0,0 +1,101 @@
+package pulse
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/machanirobotics/pulse/go/options"
+)
+
+// newTestPulse returns a Pulse without OTLP export that records to an MCAP file in a temporary directory
+func newTestPulse(t *testing.T) *Pulse {
+	t.Helper()
+
+	opts := options.Default()
+	opts.Telemetry.OTLP.Enabled = false
+	opts.Foxglove.Enabled = true
+	opts.Foxglove.McapPath = filepath.Join(t.TempDir(), "test.mcap")
+
+	p, err := New(context.Background(), options.ServiceOptions{Name: "test", Version: "1.0.0"}, opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p
+}
+
+func TestCloseTwice(t *testing.T) {
+	p := newTestPulse(t)
+
+	errHook := errors.New("hook failed")
+	var calls atomic.Int32
+	p.OnShutdown(func(context.Context) error {
+		calls.Add(1)
+		return errHook
+	})
+
+	first := p.Close(context.Background())
+	second := p.Close(context.Background())
+
+	if !errors.Is(first, errHook) {
+		t.Errorf("first Close() error = %v, want %v", first, errHook)
+	}
+	if second != first {
+		t.Errorf("second Close() error = %v, want the first result %v", second, first)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("shutdown hook ran %d times, want 1", got)
+	}
+}
+
+func TestCloseConcurrent(t *testing.T) {
+	p := newTestPulse(t)
+
+	var calls atomic.Int32
+	p.OnShutdown(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	const closers = 8
+	errs := make([]error, closers)
+	var wg sync.WaitGroup
+	for i := 0; i < closers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = p.Close(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Close() #%d error = %v", i, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("shutdown hook ran %d times, want 1", got)
+	}
+}
+
+func TestCloseDerived(t *testing.T) {
+	p := newTestPulse(t)
+	defer p.Close(context.Background())
+
+	var calls atomic.Int32
+	p.OnShutdown(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	if err := p.WithContext(context.Background()).Close(context.Background()); err != nil {
+		t.Errorf("derived Close() error = %v", err)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("derived Close ran the shutdown hook %d times, want 0", got)
+	}
+}
@@ -0,0 +1,58 @@
+package pulse
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// panicLog is logged by RecoveryMiddleware for every recovered panic
+type panicLog struct {
+	Panic  string `json:"panic" pulse:"attribute:panic"`
+	Method string `json:"method" pulse:"attribute:http.method"`
+	Path   string `json:"path" pulse:"attribute:http.path"`
+	Stack  string `json:"stack"`
+}
+
+// panicMetric is recorded by RecoveryMiddleware for every recovered panic
+type panicMetric struct {
+	Count  int    `pulse:"metric:counter:panics.total"`
+	Method string `pulse:"attribute:http.method"`
+}
+
+// RecoveryMiddleware returns an HTTP middleware that recovers panics in next.
+// A recovered panic is recorded on the span from the request context (error status and an
+// exception event with the stack trace), logged at error level with trace correlation, counted
+// in the panics.total counter, and answered with 500 Internal Server Error.
+// http.ErrAbortHandler is re-panicked so the server can abort the response as usual.
+func (p *Pulse) RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			ctx := r.Context()
+			stack := debug.Stack()
+
+			p.Tracing.SpanFromContext(ctx).RecordPanic(rec, stack)
+
+			p.Logger.WithContext(ctx).Error("Recovered from panic", panicLog{
+				Panic:  fmt.Sprint(rec),
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Stack:  string(stack),
+			})
+
+			_ = p.Metrics.RecordCtx(ctx, panicMetric{Count: 1, Method: r.Method}) // Ignore error, recovery must not fail
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
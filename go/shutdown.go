@@ -0,0 +1,39 @@
+package pulse
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// shutdownHooks holds the callbacks registered with OnShutdown, shared with instances from WithContext
+type shutdownHooks struct {
+	mu  sync.Mutex
+	fns []func(context.Context) error
+}
+
+// OnShutdown registers fn to run during Close, before the profiler, MCAP writers and telemetry
+// providers are shut down, so extensions (custom sinks, connections) can still log and flush.
+// Callbacks run in LIFO order, like defer; every callback runs and their errors are joined into
+// the error returned by Close. Callbacks registered while or after Close runs are not called.
+func (p *Pulse) OnShutdown(fn func(ctx context.Context) error) {
+	p.shutdown.mu.Lock()
+	defer p.shutdown.mu.Unlock()
+	p.shutdown.fns = append(p.shutdown.fns, fn)
+}
+
+// run calls the registered callbacks in LIFO order and returns their joined errors
+func (h *shutdownHooks) run(ctx context.Context) error {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	var errs []error
+	for i := len(fns) - 1; i >= 0; i-- {
+		if err := fns[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
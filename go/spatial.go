@@ -0,0 +1,57 @@
+package pulse
+
+import "github.com/machanirobotics/pulse/go/internal/foxglove"
+
+// Type aliases for the Foxglove spatial messages recorded with RecordPose, RecordTransform and RecordSceneUpdate
+type (
+	FoxgloveTimestamp   = foxglove.Timestamp
+	FoxgloveDuration    = foxglove.Duration
+	Vector3             = foxglove.Vector3
+	Quaternion          = foxglove.Quaternion
+	Pose                = foxglove.Pose
+	Color               = foxglove.Color
+	PoseInFrame         = foxglove.PoseInFrame
+	FrameTransform      = foxglove.FrameTransform
+	SceneUpdate         = foxglove.SceneUpdate
+	SceneEntity         = foxglove.SceneEntity
+	SceneEntityDeletion = foxglove.SceneEntityDeletion
+	KeyValuePair        = foxglove.KeyValuePair
+	ArrowPrimitive      = foxglove.ArrowPrimitive
+	CubePrimitive       = foxglove.CubePrimitive
+	SpherePrimitive     = foxglove.SpherePrimitive
+	TextPrimitive       = foxglove.TextPrimitive
+)
+
+// Deletion types of SceneEntityDeletion
+const (
+	DeleteMatchingID = foxglove.DeleteMatchingID // Delete the entity with the given ID
+	DeleteAll        = foxglove.DeleteAll        // Delete every entity on the topic
+)
+
+// RecordPose writes a pose to the MCAP recording on topic (foxglove.PoseInFrame schema), shown by the 3D panel.
+// A zero timestamp is set to the current time of the MCAP clock. Nothing is written if the spatial
+// signal is not recorded (MCAP disabled, or no FoxgloveOptions.Outputs entry lists it).
+func (p *Pulse) RecordPose(topic string, pose PoseInFrame) error {
+	if p.mcap == nil || p.mcap.spatial == nil {
+		return nil
+	}
+	return p.mcap.spatial.WritePoseInFrame(topic, pose)
+}
+
+// RecordTransform writes a transform between two frames to the MCAP recording on topic
+// (foxglove.FrameTransform schema, e.g. "/tf"). Zero timestamps and the spatial signal are handled like RecordPose.
+func (p *Pulse) RecordTransform(topic string, transform FrameTransform) error {
+	if p.mcap == nil || p.mcap.spatial == nil {
+		return nil
+	}
+	return p.mcap.spatial.WriteFrameTransform(topic, transform)
+}
+
+// RecordSceneUpdate writes markers (arrows, cubes, spheres and texts) to the MCAP recording on topic
+// (foxglove.SceneUpdate schema). Zero timestamps and the spatial signal are handled like RecordPose.
+func (p *Pulse) RecordSceneUpdate(topic string, update SceneUpdate) error {
+	if p.mcap == nil || p.mcap.spatial == nil {
+		return nil
+	}
+	return p.mcap.spatial.WriteSceneUpdate(topic, update)
+}